@@ -0,0 +1,133 @@
+package greatcircle
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+/*
+RouteMultiPoint is a point of interest found along one route of a batch.
+*/
+type RouteMultiPoint struct {
+	// Route is the index of the route in the batch
+	Route int
+	MultiPoint
+}
+
+/*
+BatchMultiPointRoutePOIS searches many routes for points of interest within
+distance nautical miles, as MultiPointRoutePOIS does for each, spreading the
+legs of every route across workers goroutines; zero or fewer uses GOMAXPROCS.
+
+Results are streamed over the returned channel as they are found. The results
+for each route are those of MultiPointRoutePOIS, in the same order, but results
+for different routes are interleaved.
+
+The channel is closed when every route has been searched, or soon after ctx is
+cancelled; check ctx.Err() to tell the two apart. Observers given by opts may be
+called concurrently.
+*/
+func BatchMultiPointRoutePOIS(ctx context.Context, routes [][]Coordinate, pois []Coordinate, distance float64, workers int, opts ...POIOption) <-chan RouteMultiPoint {
+	return batchMultiPointRoutePOIS(ctx, routes, workers, newPOIOptions(opts), legPointsInReach(pois, distance))
+}
+
+/*
+BatchMultiPointRoutePOIS searches many routes for points of interest of the Index,
+as the package level BatchMultiPointRoutePOIS does.
+*/
+func (index *Index) BatchMultiPointRoutePOIS(ctx context.Context, routes [][]Coordinate, distance float64, workers int, opts ...POIOption) <-chan RouteMultiPoint {
+	return batchMultiPointRoutePOIS(ctx, routes, workers, newPOIOptions(opts), index.legPointsInReach(distance))
+}
+
+type batchLeg struct {
+	route, leg int
+	points     []MultiPoint
+}
+
+// batchRoute holds the legs of a route that are found before an earlier leg
+type batchRoute struct {
+	next    int
+	pending map[int][]MultiPoint
+	seen    map[Coordinate]bool
+}
+
+func batchMultiPointRoutePOIS(ctx context.Context, routes [][]Coordinate, workers int, options poiOptions, pointsInReach func(Coordinate, Coordinate) (ReachResults, int)) <-chan RouteMultiPoint {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	jobs := make(chan batchLeg)
+	found := make(chan batchLeg)
+	out := make(chan RouteMultiPoint)
+
+	go func() {
+		defer close(jobs)
+		for route, routePoints := range routes {
+			for leg := 0; leg+1 < len(routePoints); leg++ {
+				select {
+				case jobs <- batchLeg{route: route, leg: leg}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				if ctx.Err() != nil {
+					return
+				}
+				routePoints := routes[job.route]
+				job.points = legMultiPoints(job.leg, routePoints[job.leg], routePoints[job.leg+1], options, pointsInReach)
+				select {
+				case found <- job:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(found)
+	}()
+
+	// results are sent leg by leg in route order, so duplicates are removed
+	// just as MultiPointRoutePOIS removes them
+	go func() {
+		defer close(out)
+		states := map[int]*batchRoute{}
+		for leg := range found {
+			state := states[leg.route]
+			if state == nil {
+				state = &batchRoute{pending: map[int][]MultiPoint{}, seen: map[Coordinate]bool{}}
+				states[leg.route] = state
+			}
+			state.pending[leg.leg] = leg.points
+			for points, ok := state.pending[state.next]; ok; points, ok = state.pending[state.next] {
+				delete(state.pending, state.next)
+				state.next++
+				for _, point := range points {
+					if state.seen[point.Poi] {
+						continue
+					}
+					state.seen[point.Poi] = true
+					select {
+					case out <- RouteMultiPoint{leg.route, point}:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+			if state.next+1 >= len(routes[leg.route]) {
+				delete(states, leg.route)
+			}
+		}
+	}()
+	return out
+}
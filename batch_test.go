@@ -0,0 +1,99 @@
+package greatcircle
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// collectBatch groups the streamed results by route
+func collectBatch(results <-chan RouteMultiPoint, routes int) [][]MultiPoint {
+	byRoute := make([][]MultiPoint, routes)
+	for result := range results {
+		byRoute[result.Route] = append(byRoute[result.Route], result.MultiPoint)
+	}
+	return byRoute
+}
+
+func TestBatchMultiPointRoutePOIS(t *testing.T) {
+	random := rand.New(rand.NewSource(8))
+	pois := randomCoordinates(random, 2000)
+	routes := [][]Coordinate{{coordKSFO.Coord, coordKLAX.Coord, coordKJFK.Coord}, {coordKSFO.Coord}, {}}
+	for i := 0; i < 20; i++ {
+		routes = append(routes, randomRoute(random, 8))
+	}
+	var legs int32
+	results := BatchMultiPointRoutePOIS(context.Background(), routes, pois, 200, 4, WithObserver(func(LegTrace) {
+		atomic.AddInt32(&legs, 1)
+	}))
+	byRoute := collectBatch(results, len(routes))
+	for i, route := range routes {
+		expected := MultiPointRoutePOIS(route, pois, 200)
+		if len(expected) == 0 && len(byRoute[i]) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(byRoute[i], expected) {
+			t.Fatalf("Expected: %v, received %v", expected, byRoute[i])
+		}
+	}
+	if legs != 2+20*8 {
+		t.Fatalf("Expected %v legs observed, received %v", 2+20*8, legs)
+	}
+
+	index := NewIndex(pois)
+	byRoute = collectBatch(index.BatchMultiPointRoutePOIS(context.Background(), routes, 200, 0), len(routes))
+	for i, route := range routes {
+		expected := index.MultiPointRoutePOIS(route, 200)
+		if len(expected) == 0 && len(byRoute[i]) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(byRoute[i], expected) {
+			t.Fatalf("Expected: %v, received %v", expected, byRoute[i])
+		}
+	}
+}
+
+func TestBatchMultiPointRoutePOISCancel(t *testing.T) {
+	random := rand.New(rand.NewSource(9))
+	index := NewIndex(randomCoordinates(random, 20000))
+	var routes [][]Coordinate
+	for i := 0; i < 1000; i++ {
+		routes = append(routes, randomRoute(random, 40))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	results := index.BatchMultiPointRoutePOIS(ctx, routes, 100, 4)
+	<-results
+	cancel()
+	done := make(chan int)
+	go func() {
+		count := 0
+		for range results {
+			count++
+		}
+		done <- count
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the results to stop after cancelling")
+	}
+	if ctx.Err() == nil {
+		t.Fatalf("Expected the context to be cancelled")
+	}
+
+	// cancelled before starting, at most the legs already started are searched
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	var legs int32
+	for range index.BatchMultiPointRoutePOIS(ctx, routes, 100, 4, WithObserver(func(LegTrace) {
+		atomic.AddInt32(&legs, 1)
+	})) {
+	}
+	if legs > 4 {
+		t.Fatalf("Expected no more than 4 legs searched, received %v", legs)
+	}
+}
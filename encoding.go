@@ -0,0 +1,766 @@
+package greatcircle
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+Coordinate, NamedCoordinate and MultiPointRoute encode to JSON, text and SQL
+in decimal degrees with North and East positive, so they can be stored and
+sent without wrapper types:
+
+	JSON  {"name":"KSFO","lat":37.618889,"lon":-122.375}
+	text  KSFO +37.618889-122.375/
+	SQL   SRID=4326;POINT(-122.375 37.618889)
+
+A Coordinate is written to SQL as EWKT, which PostGIS geometry and geography
+columns accept; a NamedCoordinate or MultiPointRoute as JSON, for json or jsonb
+columns.
+*/
+
+// ErrUnsupportedFormat is returned when a value is not in any format that can be decoded.
+var ErrUnsupportedFormat = errors.New("unsupported format")
+
+// coordinateJSON is the JSON form of a Coordinate or NamedCoordinate, with the leg arriving at a point of a LegTypedRoute
+type coordinateJSON struct {
+	Name      string  `json:"name,omitempty"`
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"lon"`
+	Leg       LegType `json:"leg,omitempty"`
+}
+
+// coordinateJSONInput is the JSON objects accepted for a Coordinate or NamedCoordinate
+type coordinateJSONInput struct {
+	Type      string   `json:"type"`
+	Name      string   `json:"name"`
+	Lat       *float64 `json:"lat"`
+	Lon       *float64 `json:"lon"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+}
+
+func newCoordinateJSON(coord NamedCoordinate) coordinateJSON {
+	latitude, longitude := coord.Coord.LatLonDegrees()
+	return coordinateJSON{Name: coord.Name, Latitude: latitude, Longitude: longitude}
+}
+
+/*
+decodeCoordinateJSON decodes a coordinate written as a JSON object with lat and
+lon, or latitude and longitude; a GeoJSON Point; a [longitude, latitude] array;
+or a string in any format accepted by UnmarshalText.
+*/
+func decodeCoordinateJSON(data []byte) (NamedCoordinate, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return NamedCoordinate{}, fmt.Errorf("greatcircle: empty JSON coordinate: %w", ErrUnsupportedFormat)
+	}
+	switch data[0] {
+	case '"':
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return NamedCoordinate{}, err
+		}
+		coord, err := parseCoordinateText(text)
+		return coord.ToNamedCoordinate(), err
+	case '[':
+		var position []float64
+		if err := json.Unmarshal(data, &position); err != nil {
+			return NamedCoordinate{}, err
+		}
+		coord, err := geoJSONCoordinate(position)
+		return coord.ToNamedCoordinate(), err
+	case '{':
+		var input coordinateJSONInput
+		if err := json.Unmarshal(data, &input); err != nil {
+			return NamedCoordinate{}, err
+		}
+		if input.Type != "" {
+			return ParseGeoJSONCoordinate(data)
+		}
+		latitude, longitude := input.Lat, input.Lon
+		if latitude == nil {
+			latitude = input.Latitude
+		}
+		if longitude == nil {
+			longitude = input.Longitude
+		}
+		if latitude == nil || longitude == nil {
+			return NamedCoordinate{}, fmt.Errorf("greatcircle: JSON coordinate %s: %w", data, ErrMissingValue)
+		}
+		coord, err := FromLatLonDegrees(*latitude, *longitude)
+		return NamedCoordinate{coord, input.Name}, err
+	}
+	return NamedCoordinate{}, fmt.Errorf("greatcircle: JSON coordinate %s: %w", data, ErrUnsupportedFormat)
+}
+
+// parseCoordinateText parses any format accepted by ParseCoordinate into a normalised Coordinate
+func parseCoordinateText(text string) (Coordinate, error) {
+	coord, err := ParseCoordinate(text)
+	if err != nil {
+		return Coordinate{}, err
+	}
+	return coord.Normalize()
+}
+
+// isGeometry reports whether data looks like WKT, EWKT, WKB or hex encoded WKB
+func isGeometry(data []byte) bool {
+	text := strings.ToUpper(strings.TrimSpace(string(data)))
+	if len(data) > 0 && data[0] <= 1 {
+		return true
+	}
+	if strings.HasPrefix(text, "SRID=") || strings.HasPrefix(text, "POINT") || strings.HasPrefix(text, "LINESTRING") {
+		return true
+	}
+	decoded, err := hex.DecodeString(text)
+	return err == nil && len(decoded) >= 5 && decoded[0] <= 1
+}
+
+// sqlBytes returns the contents of a value read from a database
+func sqlBytes(src interface{}) ([]byte, error) {
+	switch src := src.(type) {
+	case string:
+		return []byte(src), nil
+	case []byte:
+		return src, nil
+	case nil:
+		return nil, fmt.Errorf("greatcircle: cannot scan NULL: %w", ErrUnsupportedFormat)
+	}
+	return nil, fmt.Errorf("greatcircle: cannot scan %T: %w", src, ErrUnsupportedFormat)
+}
+
+/*
+decodeCoordinateSQL decodes a coordinate read from a database as a POINT
+geometry, JSON, or text.
+*/
+func decodeCoordinateSQL(data []byte) (NamedCoordinate, error) {
+	if isGeometry(data) {
+		geometry, coords, err := parseGeometry(data)
+		if err != nil {
+			return NamedCoordinate{}, err
+		}
+		if geometry != wkbPoint {
+			return NamedCoordinate{}, fmt.Errorf("greatcircle: geometry is not a POINT: %w", ErrGeometry)
+		}
+		return coords[0].ToNamedCoordinate(), nil
+	}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && strings.ContainsRune(`{["`, rune(trimmed[0])) {
+		return decodeCoordinateJSON(trimmed)
+	}
+	var coord NamedCoordinate
+	err := coord.UnmarshalText(data)
+	return coord, err
+}
+
+/*
+MarshalJSON encodes the Coordinate as {"lat":37.618889,"lon":-122.375},
+in decimal degrees with North and East positive.
+*/
+func (coord Coordinate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(newCoordinateJSON(coord.ToNamedCoordinate()))
+}
+
+/*
+UnmarshalJSON decodes a Coordinate from any of:
+
+	{"lat":37.618889,"lon":-122.375}
+	{"latitude":37.618889,"longitude":-122.375}
+	[-122.375,37.618889]
+	{"type":"Point","coordinates":[-122.375,37.618889]}
+	"+37.618889-122.375/"
+
+Numbers are decimal degrees with North and East positive, and arrays are in
+GeoJSON order, longitude first. A string may be in any format accepted by
+UnmarshalText. JSON null leaves the Coordinate unchanged.
+*/
+func (coord *Coordinate) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "null" {
+		return nil
+	}
+	named, err := decodeCoordinateJSON(data)
+	if err != nil {
+		return err
+	}
+	*coord = named.Coord
+	return nil
+}
+
+/*
+MarshalText encodes the Coordinate as ISO 6709 decimal degrees,
+such as +37.618889-122.375/; see ToISO6709.
+*/
+func (coord Coordinate) MarshalText() ([]byte, error) {
+	return []byte(formatISO6709(coord, nil, ISO6709Degrees, -1)), nil
+}
+
+/*
+UnmarshalText decodes a Coordinate in any format accepted by ParseCoordinate,
+including each ISO 6709 variant.
+*/
+func (coord *Coordinate) UnmarshalText(text []byte) error {
+	parsed, err := parseCoordinateText(string(text))
+	if err != nil {
+		return err
+	}
+	*coord = parsed
+	return nil
+}
+
+/*
+Value writes the Coordinate to a database as an EWKT point,
+SRID=4326;POINT(-122.375 37.618889)
+*/
+func (coord Coordinate) Value() (driver.Value, error) {
+	return ewkt("POINT", []Coordinate{coord}), nil
+}
+
+/*
+Scan reads a Coordinate from a database: a POINT as WKT, EWKT, WKB or hex
+encoded EWKB, as PostGIS returns it; JSON as accepted by UnmarshalJSON; or text
+as accepted by UnmarshalText.
+*/
+func (coord *Coordinate) Scan(src interface{}) error {
+	data, err := sqlBytes(src)
+	if err != nil {
+		return err
+	}
+	named, err := decodeCoordinateSQL(data)
+	if err != nil {
+		return err
+	}
+	*coord = named.Coord
+	return nil
+}
+
+/*
+MarshalJSON encodes the NamedCoordinate as {"name":"KSFO","lat":37.618889,"lon":-122.375},
+in decimal degrees with North and East positive.
+*/
+func (coord NamedCoordinate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(newCoordinateJSON(coord))
+}
+
+/*
+UnmarshalJSON decodes a NamedCoordinate from the formats accepted by
+Coordinate's UnmarshalJSON, with an optional "name", or a GeoJSON
+Point Feature with a "name" property. JSON null leaves the NamedCoordinate unchanged.
+*/
+func (coord *NamedCoordinate) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "null" {
+		return nil
+	}
+	named, err := decodeCoordinateJSON(data)
+	if err != nil {
+		return err
+	}
+	*coord = named
+	return nil
+}
+
+/*
+MarshalText encodes the NamedCoordinate as its name followed by ISO 6709
+decimal degrees, such as "KSFO +37.618889-122.375/".
+*/
+func (coord NamedCoordinate) MarshalText() ([]byte, error) {
+	text := formatISO6709(coord.Coord, nil, ISO6709Degrees, -1)
+	if coord.Name != "" {
+		text = coord.Name + " " + text
+	}
+	return []byte(text), nil
+}
+
+/*
+UnmarshalText decodes a NamedCoordinate written as MarshalText does, or a
+Coordinate without a name in any format accepted by Coordinate's UnmarshalText.
+*/
+func (coord *NamedCoordinate) UnmarshalText(text []byte) error {
+	name, position := splitNamedText(string(text))
+	parsed, err := parseCoordinateText(position)
+	if err != nil {
+		return err
+	}
+	*coord = NamedCoordinate{parsed, name}
+	return nil
+}
+
+// splitNamedText splits "NAME +37.618889-122.375/" into its name and ISO 6709 position
+func splitNamedText(text string) (string, string) {
+	text = strings.TrimSpace(text)
+	space := strings.LastIndexByte(text, ' ')
+	if space < 0 {
+		return "", text
+	}
+	if _, _, err := parseISO6709(text[space+1:]); err != nil {
+		return "", text
+	}
+	return strings.TrimSpace(text[:space]), text[space+1:]
+}
+
+/*
+Value writes the NamedCoordinate to a database as JSON.
+*/
+func (coord NamedCoordinate) Value() (driver.Value, error) {
+	data, err := coord.MarshalJSON()
+	return string(data), err
+}
+
+/*
+Scan reads a NamedCoordinate from a database as JSON, text, or a POINT in any
+of the forms accepted by Coordinate's Scan.
+*/
+func (coord *NamedCoordinate) Scan(src interface{}) error {
+	data, err := sqlBytes(src)
+	if err != nil {
+		return err
+	}
+	named, err := decodeCoordinateSQL(data)
+	if err != nil {
+		return err
+	}
+	*coord = named
+	return nil
+}
+
+/*
+MarshalJSON encodes the route as a JSON array of its NamedCoordinates.
+*/
+func (route MultiPointRoute) MarshalJSON() ([]byte, error) {
+	points := make([]coordinateJSON, len(route))
+	for i, coord := range route {
+		points[i] = newCoordinateJSON(coord)
+	}
+	return json.Marshal(points)
+}
+
+/*
+UnmarshalJSON decodes a route from a JSON array of NamedCoordinates, or a
+GeoJSON LineString as accepted by ParseGeoJSONRoute. Leg types are ignored.
+JSON null leaves the route unchanged.
+*/
+func (route *MultiPointRoute) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "null" {
+		return nil
+	}
+	parsed, err := decodeRouteJSON(data)
+	if err != nil {
+		return err
+	}
+	*route = parsed.Points
+	return nil
+}
+
+/*
+MarshalJSON encodes the route as a JSON array of its NamedCoordinates, each
+with the LegType of the leg arriving at it as "leg" when it is not a great
+circle, such as "leg":"rhumb-line".
+*/
+func (route LegTypedRoute) MarshalJSON() ([]byte, error) {
+	points := make([]coordinateJSON, len(route.Points))
+	for i, coord := range route.Points {
+		points[i] = newCoordinateJSON(coord)
+		if i > 0 {
+			points[i].Leg = route.LegType(i - 1)
+		}
+	}
+	return json.Marshal(points)
+}
+
+/*
+UnmarshalJSON decodes a route from a JSON array of NamedCoordinates, each with
+an optional "leg" for the leg arriving at it, or a GeoJSON LineString as
+accepted by ParseGeoJSONRoute. JSON null leaves the route unchanged.
+*/
+func (route *LegTypedRoute) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "null" {
+		return nil
+	}
+	parsed, err := decodeRouteJSON(data)
+	if err != nil {
+		return err
+	}
+	*route = parsed
+	return nil
+}
+
+// decodeRouteJSON decodes a JSON array of NamedCoordinates with optional legs, or a GeoJSON LineString
+func decodeRouteJSON(data []byte) (LegTypedRoute, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		return ParseGeoJSONRoute(data)
+	}
+	var points []json.RawMessage
+	if err := json.Unmarshal(data, &points); err != nil {
+		return LegTypedRoute{}, err
+	}
+	parsed := LegTypedRoute{Points: make(MultiPointRoute, len(points))}
+	for i, point := range points {
+		coord, err := decodeCoordinateJSON(point)
+		if err != nil {
+			return LegTypedRoute{}, err
+		}
+		parsed.Points[i] = coord
+		var leg struct {
+			Leg LegType `json:"leg"`
+		}
+		if i > 0 && bytes.HasPrefix(bytes.TrimSpace(point), []byte("{")) {
+			if err := json.Unmarshal(point, &leg); err != nil {
+				return LegTypedRoute{}, err
+			}
+			parsed.SetLegType(i-1, leg.Leg)
+		}
+	}
+	return parsed, nil
+}
+
+/*
+MarshalText encodes the route as one line per NamedCoordinate, as
+NamedCoordinate's MarshalText does.
+*/
+func (route MultiPointRoute) MarshalText() ([]byte, error) {
+	lines := make([]string, len(route))
+	for i, coord := range route {
+		text, err := coord.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		lines[i] = string(text)
+	}
+	return []byte(strings.Join(lines, "\n")), nil
+}
+
+/*
+UnmarshalText decodes a route written one NamedCoordinate per line. Blank lines are ignored.
+*/
+func (route *MultiPointRoute) UnmarshalText(text []byte) error {
+	var coords []NamedCoordinate
+	for _, line := range strings.Split(string(text), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var coord NamedCoordinate
+		if err := coord.UnmarshalText([]byte(line)); err != nil {
+			return err
+		}
+		coords = append(coords, coord)
+	}
+	*route = NewMultiPointRoute(coords)
+	return nil
+}
+
+/*
+Value writes the route to a database as JSON.
+*/
+func (route MultiPointRoute) Value() (driver.Value, error) {
+	data, err := route.MarshalJSON()
+	return string(data), err
+}
+
+/*
+Scan reads a route from a database as JSON, as accepted by UnmarshalJSON, or a
+LINESTRING as WKT, EWKT, WKB or hex encoded EWKB.
+*/
+func (route *MultiPointRoute) Scan(src interface{}) error {
+	data, err := sqlBytes(src)
+	if err != nil {
+		return err
+	}
+	if isGeometry(data) {
+		geometry, coords, err := parseGeometry(data)
+		if err != nil {
+			return err
+		}
+		if geometry != wkbLineString {
+			return fmt.Errorf("greatcircle: geometry is not a LINESTRING: %w", ErrGeometry)
+		}
+		named := make([]NamedCoordinate, len(coords))
+		for i, coord := range coords {
+			named[i] = coord.ToNamedCoordinate()
+		}
+		*route = NewMultiPointRoute(named)
+		return nil
+	}
+	return route.UnmarshalJSON(data)
+}
+
+/*
+Radial, GPXPoint and Facility embed a Coordinate or NamedCoordinate, so they
+define their own encodings rather than promote ones that would drop their other
+fields.
+*/
+
+type radialJSON struct {
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"lon"`
+	// Bearing is in degrees true
+	Bearing float64 `json:"bearing"`
+}
+
+/*
+MarshalJSON encodes the Radial as {"lat":37.618889,"lon":-122.375,"bearing":280},
+in decimal degrees with North and East positive.
+*/
+func (radial Radial) MarshalJSON() ([]byte, error) {
+	latitude, longitude := radial.Coordinate.LatLonDegrees()
+	return json.Marshal(radialJSON{latitude, longitude, RadiansToDegrees(radial.Bearing)})
+}
+
+/*
+UnmarshalJSON decodes a Radial written as MarshalJSON does. JSON null leaves the Radial unchanged.
+*/
+func (radial *Radial) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "null" {
+		return nil
+	}
+	var input radialJSON
+	if err := json.Unmarshal(data, &input); err != nil {
+		return err
+	}
+	coord, err := FromLatLonDegrees(input.Latitude, input.Longitude)
+	if err != nil {
+		return err
+	}
+	*radial = Radial{coord, DegreesToRadians(input.Bearing)}
+	return nil
+}
+
+/*
+MarshalText encodes the Radial as ISO 6709 decimal degrees followed by the
+bearing in degrees true, such as "+37.618889-122.375/ 280".
+*/
+func (radial Radial) MarshalText() ([]byte, error) {
+	return []byte(formatISO6709(radial.Coordinate, nil, ISO6709Degrees, -1) + " " + strconv.FormatFloat(RadiansToDegrees(radial.Bearing), 'f', -1, 64)), nil
+}
+
+/*
+UnmarshalText decodes a Radial written as MarshalText does.
+*/
+func (radial *Radial) UnmarshalText(text []byte) error {
+	fields := strings.Fields(string(text))
+	if len(fields) != 2 {
+		return &ParseError{string(text), len(text), ErrMissingValue}
+	}
+	coord, _, err := parseISO6709(fields[0])
+	if err != nil {
+		return err
+	}
+	bearing, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return &ParseError{string(text), strings.LastIndex(string(text), fields[1]), ErrUnexpectedCharacter}
+	}
+	*radial = Radial{coord, DegreesToRadians(bearing)}
+	return nil
+}
+
+/*
+Value writes the Radial to a database as JSON.
+*/
+func (radial Radial) Value() (driver.Value, error) {
+	data, err := radial.MarshalJSON()
+	return string(data), err
+}
+
+/*
+Scan reads a Radial from a database as JSON.
+*/
+func (radial *Radial) Scan(src interface{}) error {
+	data, err := sqlBytes(src)
+	if err != nil {
+		return err
+	}
+	return radial.UnmarshalJSON(data)
+}
+
+type gpxPointJSON struct {
+	coordinateJSON
+	Elevation *float64   `json:"ele,omitempty"`
+	Time      *time.Time `json:"time,omitempty"`
+}
+
+/*
+MarshalJSON encodes the GPXPoint as NamedCoordinate's MarshalJSON does, with
+"ele" in metres and "time" when they are recorded.
+*/
+func (point GPXPoint) MarshalJSON() ([]byte, error) {
+	output := gpxPointJSON{coordinateJSON: newCoordinateJSON(point.NamedCoordinate), Elevation: point.Elevation}
+	if !point.Time.IsZero() {
+		output.Time = &point.Time
+	}
+	return json.Marshal(output)
+}
+
+/*
+UnmarshalJSON decodes a GPXPoint written as MarshalJSON does. JSON null leaves the GPXPoint unchanged.
+*/
+func (point *GPXPoint) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "null" {
+		return nil
+	}
+	var named NamedCoordinate
+	if err := named.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	var input struct {
+		Elevation *float64   `json:"ele"`
+		Time      *time.Time `json:"time"`
+	}
+	if err := json.Unmarshal(data, &input); err != nil {
+		return err
+	}
+	*point = GPXPoint{NamedCoordinate: named, Elevation: input.Elevation}
+	if input.Time != nil {
+		point.Time = *input.Time
+	}
+	return nil
+}
+
+/*
+MarshalText encodes the GPXPoint as NamedCoordinate's MarshalText does, with
+the elevation as the ISO 6709 altitude, such as "KSFO +37.618889-122.375+4/".
+The Time is not kept.
+*/
+func (point GPXPoint) MarshalText() ([]byte, error) {
+	text := formatISO6709(point.Coord, point.Elevation, ISO6709Degrees, -1)
+	if point.Name != "" {
+		text = point.Name + " " + text
+	}
+	return []byte(text), nil
+}
+
+/*
+UnmarshalText decodes a GPXPoint written as MarshalText does.
+*/
+func (point *GPXPoint) UnmarshalText(text []byte) error {
+	name, position := splitNamedText(string(text))
+	if coord, elevation, err := parseISO6709(position); err == nil {
+		*point = GPXPoint{NamedCoordinate: NamedCoordinate{coord, name}, Elevation: elevation}
+		return nil
+	}
+	var named NamedCoordinate
+	if err := named.UnmarshalText(text); err != nil {
+		return err
+	}
+	*point = GPXPoint{NamedCoordinate: named}
+	return nil
+}
+
+/*
+Value writes the GPXPoint to a database as JSON.
+*/
+func (point GPXPoint) Value() (driver.Value, error) {
+	data, err := point.MarshalJSON()
+	return string(data), err
+}
+
+/*
+Scan reads a GPXPoint from a database as JSON.
+*/
+func (point *GPXPoint) Scan(src interface{}) error {
+	data, err := sqlBytes(src)
+	if err != nil {
+		return err
+	}
+	return point.UnmarshalJSON(data)
+}
+
+type facilityJSON struct {
+	coordinateJSON
+	Type        string   `json:"kind,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Elevation   *float64 `json:"elevation,omitempty"`
+	Country     string   `json:"country,omitempty"`
+	ICAO        string   `json:"icao,omitempty"`
+	IATA        string   `json:"iata,omitempty"`
+	Navaid      bool     `json:"navaid,omitempty"`
+}
+
+/*
+MarshalJSON encodes the Facility as NamedCoordinate's MarshalJSON does, with
+"kind" for the Type, as "type" would be read as GeoJSON, "description",
+"elevation" in feet, "country", "icao", "iata" and "navaid" when they are known.
+*/
+func (facility Facility) MarshalJSON() ([]byte, error) {
+	return json.Marshal(facilityJSON{
+		coordinateJSON: newCoordinateJSON(facility.NamedCoordinate),
+		Type:           facility.Type,
+		Description:    facility.Description,
+		Elevation:      facility.Elevation,
+		Country:        facility.Country,
+		ICAO:           facility.ICAO,
+		IATA:           facility.IATA,
+		Navaid:         facility.Navaid,
+	})
+}
+
+/*
+UnmarshalJSON decodes a Facility written as MarshalJSON does. JSON null leaves the Facility unchanged.
+*/
+func (facility *Facility) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "null" {
+		return nil
+	}
+	var named NamedCoordinate
+	if err := named.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	var input facilityJSON
+	if err := json.Unmarshal(data, &input); err != nil {
+		return err
+	}
+	*facility = Facility{
+		NamedCoordinate: named,
+		Type:            input.Type,
+		Description:     input.Description,
+		Elevation:       input.Elevation,
+		Country:         input.Country,
+		ICAO:            input.ICAO,
+		IATA:            input.IATA,
+		Navaid:          input.Navaid,
+	}
+	return nil
+}
+
+/*
+MarshalText encodes the Facility as NamedCoordinate's MarshalText does, such as
+"KSFO +37.618889-122.375/". Only the identifier and position are kept.
+*/
+func (facility Facility) MarshalText() ([]byte, error) {
+	return facility.NamedCoordinate.MarshalText()
+}
+
+/*
+UnmarshalText decodes a Facility written as MarshalText does.
+*/
+func (facility *Facility) UnmarshalText(text []byte) error {
+	var named NamedCoordinate
+	if err := named.UnmarshalText(text); err != nil {
+		return err
+	}
+	*facility = Facility{NamedCoordinate: named}
+	return nil
+}
+
+/*
+Value writes the Facility to a database as JSON.
+*/
+func (facility Facility) Value() (driver.Value, error) {
+	data, err := facility.MarshalJSON()
+	return string(data), err
+}
+
+/*
+Scan reads a Facility from a database as JSON.
+*/
+func (facility *Facility) Scan(src interface{}) error {
+	data, err := sqlBytes(src)
+	if err != nil {
+		return err
+	}
+	return facility.UnmarshalJSON(data)
+}
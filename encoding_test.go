@@ -0,0 +1,363 @@
+package greatcircle
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCoordinateJSON(t *testing.T) {
+	coord, _ := FromLatLonDegrees(37.5, -122.25)
+	data, err := json.Marshal(coord)
+	if err != nil {
+		t.Fatalf("Error encoding JSON; error %v", err)
+	}
+	if expected := `{"lat":37.5,"lon":-122.25}`; string(data) != expected {
+		t.Fatalf("Expected: %s, received %s", expected, data)
+	}
+
+	inputs := []string{
+		`{"lat":37.5,"lon":-122.25}`,
+		`{"latitude":37.5,"longitude":-122.25}`,
+		`[-122.25,37.5]`,
+		`{"type":"Point","coordinates":[-122.25,37.5]}`,
+		`"+37.5-122.25/"`,
+		`"37.5N 122.25W"`,
+	}
+	for _, input := range inputs {
+		var result Coordinate
+		if err := json.Unmarshal([]byte(input), &result); err != nil {
+			t.Fatalf("Error decoding %s; error %v", input, err)
+		}
+		if !result.Equal(coord) {
+			t.Fatalf("Expected: %v, received %v from %s", coord, result, input)
+		}
+	}
+
+	result := coord
+	if err := json.Unmarshal([]byte(`null`), &result); err != nil || result != coord {
+		t.Fatalf("Expected: %v unchanged, received %v, %v", coord, result, err)
+	}
+	if err := json.Unmarshal([]byte(`{"lat":37.5}`), &result); !errors.Is(err, ErrMissingValue) {
+		t.Fatalf("Expected: %v, received %v", ErrMissingValue, err)
+	}
+	if err := json.Unmarshal([]byte(`{"lat":95,"lon":0}`), &result); !errors.Is(err, ErrInvalidLatitude) {
+		t.Fatalf("Expected: %v, received %v", ErrInvalidLatitude, err)
+	}
+}
+
+func TestNamedCoordinateJSON(t *testing.T) {
+	coord := coordKLAX
+	data, err := json.Marshal(coord)
+	if err != nil {
+		t.Fatalf("Error encoding JSON; error %v", err)
+	}
+	if !bytes.Contains(data, []byte(`"name":"KLAX"`)) || bytes.Contains(data, []byte(`"leg"`)) {
+		t.Fatalf("Expected a name and no leg, received %s", data)
+	}
+	var result NamedCoordinate
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Error decoding JSON; error %v", err)
+	}
+	if !result.Coord.Equal(coord.Coord) || result.Name != "KLAX" {
+		t.Fatalf("Expected: %v, received %v", coord, result)
+	}
+
+	data, _ = coordKSFO.ToGeoJSON()
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Error decoding GeoJSON; error %v", err)
+	}
+	if !result.Coord.Equal(coordKSFO.Coord) || result.Name != "KSFO" {
+		t.Fatalf("Expected: %v, received %v", coordKSFO, result)
+	}
+}
+
+func TestMultiPointRouteJSON(t *testing.T) {
+	points := NewMultiPointRoute([]NamedCoordinate{coordKSFO, coordKLAX, coordKJFK})
+	data, err := json.Marshal(points)
+	if err != nil {
+		t.Fatalf("Error encoding JSON; error %v", err)
+	}
+	if bytes.Contains(data, []byte(`"leg"`)) {
+		t.Fatalf("Expected no leg types, received %s", data)
+	}
+	var pointsResult MultiPointRoute
+	if err := json.Unmarshal(data, &pointsResult); err != nil {
+		t.Fatalf("Error decoding JSON; error %v", err)
+	}
+	if len(pointsResult) != len(points) || pointsResult[2].Name != "KJFK" || !pointsResult[2].Coord.Equal(coordKJFK.Coord) {
+		t.Fatalf("Expected: %v, received %v", points, pointsResult)
+	}
+
+	route := points.WithLegTypes(GreatCircleLeg, RhumbLineLeg)
+	data, err = json.Marshal(route)
+	if err != nil {
+		t.Fatalf("Error encoding JSON; error %v", err)
+	}
+	var result LegTypedRoute
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Error decoding JSON; error %v", err)
+	}
+	if !bytes.Contains(data, []byte(`"leg":"rhumb-line"`)) {
+		t.Fatalf("Expected a rhumb line leg, received %s", data)
+	}
+	if len(result.Points) != len(route.Points) {
+		t.Fatalf("Expected: %v, received %v", route, result)
+	}
+	for i := range route.Points {
+		if !result.Points[i].Coord.Equal(route.Points[i].Coord) || result.Points[i].Name != route.Points[i].Name {
+			t.Fatalf("Expected: %v, received %v", route.Points[i], result.Points[i])
+		}
+	}
+	for leg := 0; leg < 2; leg++ {
+		if result.LegType(leg) != route.LegType(leg) {
+			t.Fatalf("Expected: %v, received %v", route.LegType(leg), result.LegType(leg))
+		}
+	}
+
+	data, _ = route.ToGeoJSON()
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Error decoding GeoJSON; error %v", err)
+	}
+	if len(result.Points) != 3 || result.LegType(1) != RhumbLineLeg {
+		t.Fatalf("Expected: %v, received %v", route, result)
+	}
+}
+
+func TestCoordinateText(t *testing.T) {
+	coord, _ := FromLatLonDegrees(37.625, -122.375)
+	text, _ := coord.MarshalText()
+	if expected := "+37.625-122.375/"; string(text) != expected {
+		t.Fatalf("Expected: %s, received %s", expected, text)
+	}
+	south, _ := FromLatLonDegrees(-3.5, 7.25)
+	text, _ = south.MarshalText()
+	if expected := "-03.5+007.25/"; string(text) != expected {
+		t.Fatalf("Expected: %s, received %s", expected, text)
+	}
+
+	var result Coordinate
+	if err := result.UnmarshalText(text); err != nil || !result.Equal(south) {
+		t.Fatalf("Expected: %v, received %v, %v", south, result, err)
+	}
+	if err := result.UnmarshalText([]byte("+37.5-122.25+10CRSWGS_84/")); err != nil {
+		t.Fatalf("Error decoding ISO 6709 with altitude; error %v", err)
+	}
+	expected, _ := FromLatLonDegrees(37.5, -122.25)
+	if !result.Equal(expected) {
+		t.Fatalf("Expected: %v, received %v", expected, result)
+	}
+	if err := result.UnmarshalText([]byte("+7.5-122.25/")); err == nil {
+		t.Fatalf("Expected an error for a latitude without two integer digits")
+	}
+
+	var named NamedCoordinate
+	text, _ = coordKSFO.MarshalText()
+	if err := named.UnmarshalText(text); err != nil {
+		t.Fatalf("Error decoding %s; error %v", text, err)
+	}
+	if named.Name != "KSFO" || !named.Coord.Equal(coordKSFO.Coord) {
+		t.Fatalf("Expected: %v, received %v", coordKSFO, named)
+	}
+	if err := named.UnmarshalText([]byte(`37°37'00"N 122°22'00"W`)); err != nil || named.Name != "" {
+		t.Fatalf("Expected an unnamed coordinate, received %v, %v", named, err)
+	}
+
+	route := NewMultiPointRoute([]NamedCoordinate{coordKSFO, coordKLAX})
+	text, _ = route.MarshalText()
+	var routeResult MultiPointRoute
+	if err := routeResult.UnmarshalText(text); err != nil {
+		t.Fatalf("Error decoding %s; error %v", text, err)
+	}
+	if len(routeResult) != 2 || routeResult[1].Name != "KLAX" || !routeResult[1].Coord.Equal(coordKLAX.Coord) {
+		t.Fatalf("Expected: %v, received %v", route, routeResult)
+	}
+}
+
+// ewkbPoint builds the hex EWKB PostGIS returns for a WGS 84 point
+func ewkbPoint(longitude, latitude float64) string {
+	data := []byte{1}
+	data = binary.LittleEndian.AppendUint32(data, wkbPoint|0x20000000)
+	data = binary.LittleEndian.AppendUint32(data, 4326)
+	data = binary.LittleEndian.AppendUint64(data, math.Float64bits(longitude))
+	data = binary.LittleEndian.AppendUint64(data, math.Float64bits(latitude))
+	return hex.EncodeToString(data)
+}
+
+func TestCoordinateSQL(t *testing.T) {
+	coord, _ := FromLatLonDegrees(37.5, -122.25)
+	value, err := coord.Value()
+	if expected := "SRID=4326;POINT(-122.25 37.5)"; value != expected || err != nil {
+		t.Fatalf("Expected: %s, received %v, %v", expected, value, err)
+	}
+
+	ewkb := ewkbPoint(-122.25, 37.5)
+	if expected := "0101000020E6100000"; !bytes.EqualFold([]byte(ewkb[:18]), []byte(expected)) {
+		t.Fatalf("Expected: %s, received %s", expected, ewkb[:18])
+	}
+	raw, _ := hex.DecodeString(ewkb)
+	sources := []interface{}{
+		value,
+		"POINT(-122.25 37.5)",
+		"POINT Z (-122.25 37.5 10)",
+		ewkb,
+		[]byte(ewkb),
+		raw,
+		`{"lat":37.5,"lon":-122.25}`,
+		"+37.5-122.25/",
+	}
+	for _, src := range sources {
+		var result Coordinate
+		if err := result.Scan(src); err != nil {
+			t.Fatalf("Error scanning %v; error %v", src, err)
+		}
+		if !result.Equal(coord) {
+			t.Fatalf("Expected: %v, received %v from %v", coord, result, src)
+		}
+	}
+
+	var result Coordinate
+	if err := result.Scan(nil); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Fatalf("Expected: %v, received %v", ErrUnsupportedFormat, err)
+	}
+	if err := result.Scan(42); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Fatalf("Expected: %v, received %v", ErrUnsupportedFormat, err)
+	}
+	if err := result.Scan("LINESTRING(0 0,1 1)"); !errors.Is(err, ErrGeometry) {
+		t.Fatalf("Expected: %v, received %v", ErrGeometry, err)
+	}
+}
+
+func TestNamedCoordinateAndRouteSQL(t *testing.T) {
+	value, err := coordKSFO.Value()
+	if err != nil {
+		t.Fatalf("Error writing value; error %v", err)
+	}
+	var named NamedCoordinate
+	if err := named.Scan(value); err != nil || named.Name != "KSFO" || !named.Coord.Equal(coordKSFO.Coord) {
+		t.Fatalf("Expected: %v, received %v, %v", coordKSFO, named, err)
+	}
+
+	route := NewMultiPointRoute([]NamedCoordinate{coordKSFO, coordKLAX})
+	value, err = route.Value()
+	if err != nil {
+		t.Fatalf("Error writing value; error %v", err)
+	}
+	var result MultiPointRoute
+	if err := result.Scan(value); err != nil || len(result) != 2 || result[0].Name != "KSFO" {
+		t.Fatalf("Expected: %v, received %v, %v", route, result, err)
+	}
+	if err := result.Scan([]byte("SRID=4326;LINESTRING(-122.366667 37.616667,-118.4 33.95)")); err != nil {
+		t.Fatalf("Error scanning LINESTRING; error %v", err)
+	}
+	if len(result) != 2 || !result[1].Coord.Equal(coordKLAX.Coord) {
+		t.Fatalf("Expected: %v, received %v", route, result)
+	}
+}
+
+func TestRadialAndGPXPointEncoding(t *testing.T) {
+	radial := Radial{coordKSFO.Coord, DegreesToRadians(280)}
+	data, err := json.Marshal(radial)
+	if err != nil {
+		t.Fatalf("Error encoding JSON; error %v", err)
+	}
+	var radialResult Radial
+	if err := json.Unmarshal(data, &radialResult); err != nil {
+		t.Fatalf("Error decoding JSON; error %v", err)
+	}
+	if !radialResult.Equal(radial.Coordinate) || math.Abs(radialResult.Bearing-radial.Bearing) > 1e-12 {
+		t.Fatalf("Expected: %v, received %v", radial, radialResult)
+	}
+	text, _ := radial.MarshalText()
+	if err := radialResult.UnmarshalText(text); err != nil || math.Abs(radialResult.Bearing-radial.Bearing) > 1e-12 {
+		t.Fatalf("Expected: %v, received %v from %s, %v", radial, radialResult, text, err)
+	}
+
+	elevation := 4.0
+	ksfo, _ := FromLatLonDegrees(37.625, -122.375)
+	point := GPXPoint{NamedCoordinate: NamedCoordinate{ksfo, "KSFO"}, Elevation: &elevation, Time: time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)}
+	data, err = json.Marshal(point)
+	if err != nil {
+		t.Fatalf("Error encoding JSON; error %v", err)
+	}
+	var pointResult GPXPoint
+	if err := json.Unmarshal(data, &pointResult); err != nil {
+		t.Fatalf("Error decoding JSON; error %v", err)
+	}
+	if pointResult.Name != "KSFO" || pointResult.Elevation == nil || *pointResult.Elevation != 4 || !pointResult.Time.Equal(point.Time) {
+		t.Fatalf("Expected: %v, received %v from %s", point, pointResult, data)
+	}
+	text, _ = point.MarshalText()
+	if expected := "KSFO +37.625-122.375+4/"; string(text) != expected {
+		t.Fatalf("Expected: %s, received %s", expected, text)
+	}
+	pointResult = GPXPoint{}
+	if err := pointResult.UnmarshalText(text); err != nil || pointResult.Elevation == nil || *pointResult.Elevation != 4 {
+		t.Fatalf("Expected: %v, received %v, %v", point, pointResult, err)
+	}
+}
+
+func TestFacilityEncoding(t *testing.T) {
+	ksfo, _ := FromLatLonDegrees(37.625, -122.375)
+	elevation := 13.0
+	facility := Facility{
+		NamedCoordinate: NamedCoordinate{ksfo, "KSFO"},
+		Type:            "large_airport",
+		Description:     "San Francisco International Airport",
+		Elevation:       &elevation,
+		Country:         "US",
+		ICAO:            "KSFO",
+		IATA:            "SFO",
+	}
+	data, err := json.Marshal(facility)
+	if err != nil {
+		t.Fatalf("Error encoding JSON; error %v", err)
+	}
+	expected := `{"name":"KSFO","lat":37.625,"lon":-122.375,"kind":"large_airport","description":"San Francisco International Airport","elevation":13,"country":"US","icao":"KSFO","iata":"SFO"}`
+	if string(data) != expected {
+		t.Fatalf("Expected: %s, received %s", expected, data)
+	}
+	var result Facility
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Error decoding JSON; error %v", err)
+	}
+	if result.Type != facility.Type || result.Description != facility.Description || result.Elevation == nil || *result.Elevation != 13 ||
+		result.Country != "US" || result.ICAO != "KSFO" || result.IATA != "SFO" || !result.Coord.Equal(ksfo) {
+		t.Fatalf("Expected: %v, received %v", facility, result)
+	}
+
+	// sea level is kept, and an unknown elevation left out
+	seaLevel := 0.0
+	facility.Elevation = &seaLevel
+	if data, _ := json.Marshal(facility); !bytes.Contains(data, []byte(`"elevation":0,`)) {
+		t.Fatalf("Expected an elevation of 0, received %s", data)
+	}
+	facility.Elevation = nil
+	if data, _ := json.Marshal(facility); bytes.Contains(data, []byte(`"elevation"`)) {
+		t.Fatalf("Expected no elevation, received %s", data)
+	}
+	facility.Elevation = &elevation
+
+	value, err := facility.Value()
+	if err != nil || value != expected {
+		t.Fatalf("Expected: %s, received %v, %v", expected, value, err)
+	}
+	result = Facility{}
+	if err := result.Scan([]byte(expected)); err != nil || result.IATA != "SFO" {
+		t.Fatalf("Expected: %v, received %v, %v", facility, result, err)
+	}
+
+	text, _ := facility.MarshalText()
+	if expected := "KSFO +37.625-122.375/"; string(text) != expected {
+		t.Fatalf("Expected: %s, received %s", expected, text)
+	}
+	result = Facility{}
+	if err := result.UnmarshalText(text); err != nil || result.Name != "KSFO" || !result.Coord.Equal(ksfo) {
+		t.Fatalf("Expected: %v, received %v, %v", facility, result, err)
+	}
+}
@@ -0,0 +1,348 @@
+package greatcircle
+
+import (
+	"math"
+)
+
+/*
+Ellipsoid is a reference ellipsoid used to model the shape of the Earth
+for geodesic calculations.
+
+The spherical functions (Distance, InitialBearing) are fast but can be
+in error by up to about 0.5% over long legs. An Ellipsoid solves the same
+problems on the oblate Earth, accurate to well under a metre.
+*/
+type Ellipsoid struct {
+	Name string
+	// SemiMajorAxis is the equatorial radius in metres
+	SemiMajorAxis float64
+	// Flattening is (a - b) / a
+	Flattening float64
+}
+
+// Commonly used reference ellipsoids.
+var (
+	WGS84             = Ellipsoid{"WGS84", 6378137, 1 / 298.257223563}
+	GRS80             = Ellipsoid{"GRS80", 6378137, 1 / 298.257222101}
+	Airy1830          = Ellipsoid{"Airy1830", 6377563.396, 1 / 299.3249646}
+	Bessel1841        = Ellipsoid{"Bessel1841", 6377397.155, 1 / 299.1528128}
+	Clarke1866        = Ellipsoid{"Clarke1866", 6378206.4, 1 / 294.978698214}
+	International1924 = Ellipsoid{"International1924", 6378388, 1 / 297}
+)
+
+// metresPerNM is the length of the international nautical mile
+const metresPerNM = 1852.0
+
+/*
+EarthModel is a model of the Earth that can answer the inverse problem:
+the distance and the initial and final bearings between two Coordinates.
+
+Sphere and Ellipsoid both implement EarthModel, so callers can choose the
+fast spherical path or the precise ellipsoidal path at runtime.
+*/
+type EarthModel interface {
+	Distance(point1, point2 Coordinate) float64
+	InitialBearing(point1, point2 Coordinate) float64
+	FinalBearing(point1, point2 Coordinate) float64
+}
+
+/*
+Sphere is the spherical EarthModel used by the package level functions.
+*/
+type Sphere struct{}
+
+/*
+Distance is the same as the package level Distance; result is in nautical miles.
+*/
+func (Sphere) Distance(point1, point2 Coordinate) float64 {
+	return Distance(point1, point2)
+}
+
+/*
+InitialBearing is the same as the package level InitialBearing; result is in radians.
+*/
+func (Sphere) InitialBearing(point1, point2 Coordinate) float64 {
+	return InitialBearing(point1, point2)
+}
+
+/*
+FinalBearing is the same as the package level FinalBearing; result is in radians.
+*/
+func (Sphere) FinalBearing(point1, point2 Coordinate) float64 {
+	return FinalBearing(point1, point2)
+}
+
+/*
+Geodesic is the solution of the inverse problem between two Coordinates
+on an Ellipsoid.
+*/
+type Geodesic struct {
+	// Distance is in nautical miles
+	Distance float64
+	// InitialBearing is the true course at the first point, in radians
+	InitialBearing float64
+	// FinalBearing is the true course on arrival at the second point, in radians
+	FinalBearing float64
+}
+
+/*
+Distance calculates the length of the geodesic between two Coordinates.
+
+Result is in nautical miles.
+*/
+func (e Ellipsoid) Distance(point1, point2 Coordinate) float64 {
+	return e.Inverse(point1, point2).Distance
+}
+
+/*
+InitialBearing provides the initial true course along the geodesic from
+point1 to point2.
+
+Result is in radians, in (0, 2pi] as with the package level InitialBearing.
+*/
+func (e Ellipsoid) InitialBearing(point1, point2 Coordinate) float64 {
+	return e.Inverse(point1, point2).InitialBearing
+}
+
+/*
+FinalBearing provides the true course along the geodesic on arrival
+at point2.
+
+Result is in radians, in (0, 2pi] as with the package level FinalBearing.
+*/
+func (e Ellipsoid) FinalBearing(point1, point2 Coordinate) float64 {
+	return e.Inverse(point1, point2).FinalBearing
+}
+
+/*
+Inverse solves the inverse geodesic problem: the distance, initial and final
+bearings of the shortest path between two Coordinates on the ellipsoid.
+
+Vincenty's series are used for the distance and longitude integrals, but
+rather than Vincenty's iteration on longitude (which fails to converge for
+nearly antipodal points) the initial azimuth is found by bisection, following
+the canonical arrangement described by Karney (2013), "Algorithms for geodesics".
+This converges for every pair of points.
+*/
+func (e Ellipsoid) Inverse(point1, point2 Coordinate) Geodesic {
+	f := e.Flattening
+	lat1, lat2 := point1.Latitude, point2.Latitude
+	// this library treats West longitudes as positive; the geodesic
+	// is solved with East positive
+	lon12 := math.Remainder(point1.Longitude-point2.Longitude, 2*math.Pi)
+
+	if lat1 == lat2 && lon12 == 0 {
+		return Geodesic{0, 2 * math.Pi, 2 * math.Pi}
+	}
+
+	// Reduce the problem so that lon12 >= 0, |lat1| >= |lat2| and lat1 <= 0.
+	// The solution then has an initial azimuth between 0 and pi.
+	lonFlip := lon12 < 0
+	if lonFlip {
+		lon12 = -lon12
+	}
+	swapped := math.Abs(lat1) < math.Abs(lat2)
+	if swapped {
+		lat1, lat2 = lat2, lat1
+	}
+	latFlip := lat1 > 0
+	if latFlip {
+		lat1, lat2 = -lat1, -lat2
+	}
+
+	// reduced latitudes on the auxiliary sphere
+	beta1 := math.Atan2((1-f)*math.Sin(lat1), math.Cos(lat1))
+	beta2 := math.Atan2((1-f)*math.Sin(lat2), math.Cos(lat2))
+
+	var sol geodesicSolution
+	if beta1 == 0 && lon12 <= (1-f)*math.Pi {
+		// along the equator, where the auxiliary sphere is stretched by 1/(1-f)
+		sol = geodesicSolution{
+			alpha1:  math.Pi / 2,
+			alpha2:  math.Pi / 2,
+			sigma1:  0,
+			sigma2:  lon12 / (1 - f),
+			sinAlp0: 1,
+		}
+	} else {
+		low, high := 0.0, math.Pi
+		if beta1 == 0 {
+			// equatorial points beyond (1-f)pi apart are joined over the pole
+			low = math.Pi / 2
+		}
+		for i := 0; i < 200; i++ {
+			mid := (low + high) / 2
+			if mid <= low || mid >= high {
+				break
+			}
+			sol = e.solveAzimuth(beta1, beta2, mid)
+			if sol.lambda12 < lon12 {
+				low = mid
+			} else {
+				high = mid
+			}
+		}
+		sol = e.solveAzimuth(beta1, beta2, (low+high)/2)
+	}
+
+	distance := e.arcLength(sol.sinAlp0, sol.sigma1, sol.sigma2)
+
+	alpha1, alpha2 := sol.alpha1, sol.alpha2
+	if latFlip {
+		alpha1, alpha2 = math.Pi-alpha1, math.Pi-alpha2
+	}
+	if swapped {
+		alpha1, alpha2 = math.Pi-alpha2, math.Pi-alpha1
+	}
+	if lonFlip {
+		alpha1, alpha2 = -alpha1, -alpha2
+	}
+	return Geodesic{distance / metresPerNM, normalizeBearing(alpha1), normalizeBearing(alpha2)}
+}
+
+/*
+Direct solves the direct geodesic problem: the Coordinate reached by
+travelling distance nautical miles from point along the geodesic
+commencing on the initial true course bearing (radians).
+
+The final bearing on arrival is also returned, in radians.
+
+Uses Vincenty's (1975) direct formula, which converges for all distances.
+*/
+func (e Ellipsoid) Direct(point Coordinate, bearing, distance float64) (Coordinate, float64) {
+	a := e.SemiMajorAxis
+	f := e.Flattening
+	b := a * (1 - f)
+	s := distance * metresPerNM
+
+	sinAlpha1, cosAlpha1 := math.Sincos(bearing)
+	sinU1, cosU1 := math.Sincos(math.Atan2((1-f)*math.Sin(point.Latitude), math.Cos(point.Latitude)))
+	sigma1 := math.Atan2(sinU1, cosAlpha1*cosU1)
+	sinAlpha := cosU1 * sinAlpha1
+	cosSqAlpha := 1 - sinAlpha*sinAlpha
+	uSq := cosSqAlpha * (a*a - b*b) / (b * b)
+	A, B := vincentyAB(uSq)
+
+	sigma := s / (b * A)
+	var sinSigma, cosSigma, cos2SigmaM float64
+	for i := 0; i < 200; i++ {
+		cos2SigmaM = math.Cos(2*sigma1 + sigma)
+		sinSigma, cosSigma = math.Sincos(sigma)
+		deltaSigma := vincentyDeltaSigma(B, sinSigma, cosSigma, cos2SigmaM)
+		previous := sigma
+		sigma = s/(b*A) + deltaSigma
+		if math.Abs(sigma-previous) < 1e-12 {
+			break
+		}
+	}
+	cos2SigmaM = math.Cos(2*sigma1 + sigma)
+	sinSigma, cosSigma = math.Sincos(sigma)
+
+	tmp := sinU1*sinSigma - cosU1*cosSigma*cosAlpha1
+	lat2 := math.Atan2(sinU1*cosSigma+cosU1*sinSigma*cosAlpha1, (1-f)*math.Hypot(sinAlpha, tmp))
+	lambda := math.Atan2(sinSigma*sinAlpha1, cosU1*cosSigma-sinU1*sinSigma*cosAlpha1)
+	C := f / 16 * cosSqAlpha * (4 + f*(4-3*cosSqAlpha))
+	L := lambda - (1-C)*f*sinAlpha*(sigma+C*sinSigma*(cos2SigmaM+C*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+	alpha2 := math.Atan2(sinAlpha, -tmp)
+
+	lon2 := math.Remainder(point.Longitude-L, 2*math.Pi)
+	return Coordinate{lat2, lon2}, normalizeBearing(alpha2)
+}
+
+// geodesicSolution is a geodesic on the auxiliary sphere for a trial initial azimuth
+type geodesicSolution struct {
+	alpha1, alpha2 float64
+	sigma1, sigma2 float64
+	sinAlp0        float64
+	lambda12       float64
+}
+
+/*
+solveAzimuth follows the geodesic leaving reduced latitude beta1 on
+azimuth alpha1 until it reaches reduced latitude beta2, and reports the
+ellipsoidal longitude difference covered (lambda12).
+
+Requires beta1 <= 0, |beta2| <= |beta1| and alpha1 in [0, pi]; lambda12
+then increases monotonically with alpha1 from 0 to pi.
+*/
+func (e Ellipsoid) solveAzimuth(beta1, beta2, alpha1 float64) geodesicSolution {
+	f := e.Flattening
+	sinBeta1, cosBeta1 := math.Sincos(beta1)
+	sinBeta2, cosBeta2 := math.Sincos(beta2)
+	sinAlpha1, cosAlpha1 := math.Sincos(alpha1)
+
+	sinAlp0 := sinAlpha1 * cosBeta1
+	cosSqAlp0 := 1 - sinAlp0*sinAlp0
+
+	sigma1 := math.Atan2(sinBeta1, cosAlpha1*cosBeta1)
+	if sigma1 > 0 {
+		// only possible on the equator heading west of south
+		sigma1 -= 2 * math.Pi
+	}
+
+	// take the crossing of beta2 where the geodesic is heading north
+	var diff float64
+	if cosBeta1 < -sinBeta1 {
+		diff = (cosBeta2 - cosBeta1) * (cosBeta2 + cosBeta1)
+	} else {
+		diff = (sinBeta1 - sinBeta2) * (sinBeta1 + sinBeta2)
+	}
+	cosAlpha2 := math.Sqrt(cosAlpha1*cosAlpha1*cosBeta1*cosBeta1+diff) / cosBeta2
+	sinAlpha2 := sinAlp0 / cosBeta2
+	sigma2 := math.Atan2(sinBeta2, cosAlpha2*cosBeta2)
+
+	// longitudes on the auxiliary sphere
+	omega1 := math.Atan2(sinAlp0*math.Sin(sigma1), math.Cos(sigma1))
+	omega2 := math.Atan2(sinAlp0*math.Sin(sigma2), math.Cos(sigma2))
+
+	sigma12 := sigma2 - sigma1
+	cos2SigmaM := math.Cos(sigma1 + sigma2)
+	C := f / 16 * cosSqAlp0 * (4 + f*(4-3*cosSqAlp0))
+	lambda12 := omega2 - omega1 - (1-C)*f*sinAlp0*
+		(sigma12+C*math.Sin(sigma12)*(cos2SigmaM+C*math.Cos(sigma12)*(-1+2*cos2SigmaM*cos2SigmaM)))
+
+	return geodesicSolution{
+		alpha1:   alpha1,
+		alpha2:   math.Atan2(sinAlpha2, cosAlpha2),
+		sigma1:   sigma1,
+		sigma2:   sigma2,
+		sinAlp0:  sinAlp0,
+		lambda12: lambda12,
+	}
+}
+
+/*
+arcLength is the length in metres of the geodesic between arc lengths
+sigma1 and sigma2 on the auxiliary sphere.
+*/
+func (e Ellipsoid) arcLength(sinAlp0, sigma1, sigma2 float64) float64 {
+	a := e.SemiMajorAxis
+	b := a * (1 - e.Flattening)
+	uSq := (1 - sinAlp0*sinAlp0) * (a*a - b*b) / (b * b)
+	A, B := vincentyAB(uSq)
+	sigma := sigma2 - sigma1
+	sinSigma, cosSigma := math.Sincos(sigma)
+	deltaSigma := vincentyDeltaSigma(B, sinSigma, cosSigma, math.Cos(sigma1+sigma2))
+	return b * A * (sigma - deltaSigma)
+}
+
+// vincentyAB are the A and B coefficients of Vincenty's distance series
+func vincentyAB(uSq float64) (float64, float64) {
+	A := 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
+	B := uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
+	return A, B
+}
+
+func vincentyDeltaSigma(B, sinSigma, cosSigma, cos2SigmaM float64) float64 {
+	return B * sinSigma * (cos2SigmaM + B/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+		B/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+}
+
+// normalizeBearing returns an equivalent bearing in (0, 2pi], so that due North is 2pi rather than 0
+func normalizeBearing(bearing float64) float64 {
+	bearing = math.Mod(bearing, 2*math.Pi)
+	if bearing <= 0 {
+		bearing += 2 * math.Pi
+	}
+	return bearing
+}
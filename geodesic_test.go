@@ -0,0 +1,125 @@
+package greatcircle
+
+import (
+	"math"
+	"testing"
+)
+
+// Flinders Peak to Buninyong, the worked example from Geoscience Australia
+var flindersPeak = Coordinate{DegreesToRadians(-DegreeUnitsToDecimalDegree(37, 57, 3.72030)), DegreesToRadians(-DegreeUnitsToDecimalDegree(144, 25, 29.52440))}
+var buninyong = Coordinate{DegreesToRadians(-DegreeUnitsToDecimalDegree(37, 39, 10.15610)), DegreesToRadians(-DegreeUnitsToDecimalDegree(143, 55, 35.38390))}
+
+// bearingDifference is the smallest angle between two bearings
+func bearingDifference(bearing1, bearing2 float64) float64 {
+	return math.Abs(math.Remainder(bearing1-bearing2, 2*math.Pi))
+}
+
+var ellipsoidInverse = []struct {
+	ellipsoid      Ellipsoid
+	point1         Coordinate
+	point2         Coordinate
+	metres         float64
+	initialBearing float64
+	finalBearing   float64
+}{
+	{GRS80, flindersPeak, buninyong, 54972.271,
+		DegreesToRadians(DegreeUnitsToDecimalDegree(306, 52, 5.37)), DegreesToRadians(DegreeUnitsToDecimalDegree(307, 10, 25.07))},
+	{GRS80, buninyong, flindersPeak, 54972.271,
+		DegreesToRadians(DegreeUnitsToDecimalDegree(127, 10, 25.07)), DegreesToRadians(DegreeUnitsToDecimalDegree(126, 52, 5.37))},
+	// along the equator
+	{WGS84, Coordinate{0, 0}, Coordinate{0, DegreesToRadians(-1)}, 111319.491, math.Pi / 2, math.Pi / 2},
+	// antipodal points on the equator are joined over a pole
+	{WGS84, Coordinate{0, 0}, Coordinate{0, math.Pi}, 20003931.459, math.Pi, 0},
+	// pole to pole
+	{WGS84, Coordinate{math.Pi / 2, 0}, Coordinate{-math.Pi / 2, 0}, 20003931.459, math.Pi, math.Pi},
+}
+
+func TestEllipsoidInverse(t *testing.T) {
+	for _, v := range ellipsoidInverse {
+		result := v.ellipsoid.Inverse(v.point1, v.point2)
+		if math.Abs(result.Distance*metresPerNM-v.metres) > 0.001 {
+			t.Fatalf("Distance %v to %v expected: %v, received %v", v.point1, v.point2, v.metres, result.Distance*metresPerNM)
+		}
+		if bearingDifference(result.InitialBearing, v.initialBearing) > DegreesToRadians(0.01/3600) {
+			t.Fatalf("Initial bearing %v to %v expected: %v, received %v", v.point1, v.point2,
+				RadiansToDegrees(v.initialBearing), RadiansToDegrees(result.InitialBearing))
+		}
+		if bearingDifference(result.FinalBearing, v.finalBearing) > DegreesToRadians(0.01/3600) {
+			t.Fatalf("Final bearing %v to %v expected: %v, received %v", v.point1, v.point2,
+				RadiansToDegrees(v.finalBearing), RadiansToDegrees(result.FinalBearing))
+		}
+	}
+}
+
+func TestEllipsoidDirect(t *testing.T) {
+	result, finalBearing := GRS80.Direct(flindersPeak, DegreesToRadians(DegreeUnitsToDecimalDegree(306, 52, 5.37)), 54972.271/metresPerNM)
+	if math.Abs(result.Latitude-buninyong.Latitude) > 1e-9 || math.Abs(result.Longitude-buninyong.Longitude) > 1e-9 {
+		t.Fatalf("Expected: %v, received %v", buninyong, result)
+	}
+	expectedFinal := DegreesToRadians(DegreeUnitsToDecimalDegree(307, 10, 25.07))
+	if bearingDifference(finalBearing, expectedFinal) > DegreesToRadians(0.01/3600) {
+		t.Fatalf("Expected: %v, received %v", RadiansToDegrees(expectedFinal), RadiansToDegrees(finalBearing))
+	}
+}
+
+func TestEllipsoidNearlyAntipodal(t *testing.T) {
+	// Vincenty's method fails to converge for this pair; Karney (2013) gives 19936288.579 m
+	result := WGS84.Distance(Coordinate{0, 0}, Coordinate{DegreesToRadians(0.5), DegreesToRadians(-179.5)})
+	if math.Abs(result*metresPerNM-19936288.579) > 0.001 {
+		t.Fatalf("Expected: %v, received %v", 19936288.579, result*metresPerNM)
+	}
+
+	var nearlyAntipodal = []struct {
+		point1 Coordinate
+		point2 Coordinate
+	}{
+		{Coordinate{0, 0}, Coordinate{DegreesToRadians(0.5), DegreesToRadians(-179.5)}},
+		{Coordinate{0, 0}, Coordinate{DegreesToRadians(-0.1), DegreesToRadians(179.7)}},
+		{Coordinate{DegreesToRadians(-30), DegreesToRadians(10)}, Coordinate{DegreesToRadians(29.9), DegreesToRadians(-169.8)}},
+		{Coordinate{DegreesToRadians(41.4), DegreesToRadians(-2.2)}, Coordinate{DegreesToRadians(-41.5), DegreesToRadians(-177.7)}},
+	}
+	for _, v := range nearlyAntipodal {
+		geodesic := WGS84.Inverse(v.point1, v.point2)
+		if math.IsNaN(geodesic.Distance) || geodesic.Distance*metresPerNM > 20003931.459 {
+			t.Fatalf("Distance %v to %v is invalid: %v", v.point1, v.point2, geodesic.Distance)
+		}
+		result, _ := WGS84.Direct(v.point1, geodesic.InitialBearing, geodesic.Distance)
+		if WGS84.Distance(result, v.point2)*metresPerNM > 0.01 {
+			t.Fatalf("Expected: %v, received %v", v.point2, result)
+		}
+	}
+}
+
+func TestEllipsoidSelectable(t *testing.T) {
+	north := Coordinate{coordKLAX.Coord.Latitude + 0.1, coordKLAX.Coord.Longitude}
+	cases := []struct {
+		model        EarthModel
+		distance     float64
+		bearing      float64
+		finalBearing float64
+	}{
+		{Sphere{}, 2143.726101, 65.892167, 93.858164},
+		{WGS84, 2149.892342, 65.933549, 93.903414},
+	}
+	for _, c := range cases {
+		result := c.model.Distance(coordKLAX.Coord, coordKJFK.Coord)
+		if math.Abs(result-c.distance) > 1e-5 {
+			t.Fatalf("Distance KLAX KJFK expected: %v, received %v", c.distance, result)
+		}
+		bearing := RadiansToDegrees(c.model.InitialBearing(coordKLAX.Coord, coordKJFK.Coord))
+		if math.Abs(bearing-c.bearing) > 1e-5 {
+			t.Fatalf("Initial bearing KLAX KJFK expected: %v, received %v", c.bearing, bearing)
+		}
+		finalBearing := RadiansToDegrees(c.model.FinalBearing(coordKLAX.Coord, coordKJFK.Coord))
+		if math.Abs(finalBearing-c.finalBearing) > 1e-5 {
+			t.Fatalf("Final bearing KLAX KJFK expected: %v, received %v", c.finalBearing, finalBearing)
+		}
+		// due North is 2pi for every model
+		if bearing := c.model.InitialBearing(coordKLAX.Coord, north); math.Abs(bearing-2*math.Pi) > 1e-6 {
+			t.Fatalf("Initial bearing due North expected: %v, received %v", 2*math.Pi, bearing)
+		}
+	}
+	if bearing := WGS84.FinalBearing(coordKLAX.Coord, north); math.Abs(bearing-2*math.Pi) > 1e-6 {
+		t.Fatalf("Final bearing due North expected: %v, received %v", 2*math.Pi, bearing)
+	}
+}
@@ -0,0 +1,233 @@
+package greatcircle
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+/*
+A geohash names a cell of a grid over latitude and longitude by interleaving
+the bits of each, longitude first, and writing them five at a time in base 32.
+Each character added divides the cell into 32, so cells that share a prefix
+are near each other, which makes geohashes useful keys for storing points.
+*/
+
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// GeohashMaxPrecision is the longest geohash written, a cell a few centimetres across.
+const GeohashMaxPrecision = 12
+
+/*
+Cell is an area named by a geohash, Maidenhead locator or similar code: the
+Coordinates of its centre and its south west and north east corners.
+*/
+type Cell struct {
+	Centre    Coordinate
+	SouthWest Coordinate
+	NorthEast Coordinate
+}
+
+/*
+Contains reports whether coord is within the Cell, including its edges.
+*/
+func (cell Cell) Contains(coord Coordinate) bool {
+	south, west := cell.SouthWest.LatLonDegrees()
+	north, east := cell.NorthEast.LatLonDegrees()
+	latitude, longitude := coord.LatLonDegrees()
+	if latitude < south || latitude > north {
+		return false
+	}
+	if east < west {
+		// the Cell spans the antimeridian
+		return longitude >= west || longitude <= east
+	}
+	return longitude >= west && longitude <= east
+}
+
+// newCell creates a Cell from its bounds in degrees, East positive
+func newCell(south, west, north, east float64) Cell {
+	centre, _ := FromLatLonDegrees((south+north)/2, (west+east)/2)
+	southWest, _ := FromLatLonDegrees(south, west)
+	northEast, _ := FromLatLonDegrees(north, east)
+	return Cell{centre, southWest, northEast}
+}
+
+/*
+ToGeohash returns the geohash of the cell containing the Coordinate, of
+precision characters from 1 to GeohashMaxPrecision.
+*/
+func (coord Coordinate) ToGeohash(precision int) string {
+	if precision < 1 {
+		precision = 1
+	}
+	if precision > GeohashMaxPrecision {
+		precision = GeohashMaxPrecision
+	}
+	latitude, longitude := coord.LatLonDegrees()
+	south, north := -90.0, 90.0
+	west, east := -180.0, 180.0
+	var hash strings.Builder
+	bits, character := 0, 0
+	for i := 0; hash.Len() < precision; i++ {
+		character = character << 1
+		if i%2 == 0 {
+			middle := (west + east) / 2
+			if longitude >= middle {
+				character = character | 1
+				west = middle
+			} else {
+				east = middle
+			}
+		} else {
+			middle := (south + north) / 2
+			if latitude >= middle {
+				character = character | 1
+				south = middle
+			} else {
+				north = middle
+			}
+		}
+		bits++
+		if bits == 5 {
+			hash.WriteByte(geohashAlphabet[character])
+			bits, character = 0, 0
+		}
+	}
+	return hash.String()
+}
+
+/*
+DecodeGeohash returns the Cell a geohash names. Upper case is accepted. An
+error wrapping ErrGridReference is returned for a character that is not in a
+geohash.
+*/
+func DecodeGeohash(hash string) (Cell, error) {
+	if hash == "" || len(hash) > GeohashMaxPrecision {
+		return Cell{}, fmt.Errorf("greatcircle: geohash %q: %w", hash, ErrGridReference)
+	}
+	south, north := -90.0, 90.0
+	west, east := -180.0, 180.0
+	even := true
+	for i := 0; i < len(hash); i++ {
+		value := strings.IndexByte(geohashAlphabet, strings.ToLower(hash[i : i+1])[0])
+		if value < 0 {
+			return Cell{}, &ParseError{hash, i, ErrGridReference}
+		}
+		for bit := 4; bit >= 0; bit-- {
+			set := value>>uint(bit)&1 == 1
+			if even {
+				middle := (west + east) / 2
+				if set {
+					west = middle
+				} else {
+					east = middle
+				}
+			} else {
+				middle := (south + north) / 2
+				if set {
+					south = middle
+				} else {
+					north = middle
+				}
+			}
+			even = !even
+		}
+	}
+	return newCell(south, west, north, east), nil
+}
+
+/*
+GeohashNeighbours returns the geohashes of the same precision surrounding a
+geohash, clockwise from the north: north, north east, east, south east, south,
+south west, west and north west. Cells beyond a pole are left out, so a cell
+touching a pole has five neighbours.
+*/
+func GeohashNeighbours(hash string) ([]string, error) {
+	cell, err := DecodeGeohash(hash)
+	if err != nil {
+		return nil, err
+	}
+	return geohashNeighbours(cell, len(hash)), nil
+}
+
+func geohashNeighbours(cell Cell, precision int) []string {
+	south, west := cell.SouthWest.LatLonDegrees()
+	north, east := cell.NorthEast.LatLonDegrees()
+	latitude, longitude := cell.Centre.LatLonDegrees()
+	height, width := north-south, east-west
+	if width <= 0 {
+		width = width + 360
+	}
+	var neighbours []string
+	for _, step := range [][2]float64{{1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}, {0, -1}, {1, -1}} {
+		neighbourLatitude := latitude + step[0]*height
+		if neighbourLatitude > 90 || neighbourLatitude < -90 {
+			continue
+		}
+		neighbour, _ := FromLatLonDegrees(neighbourLatitude, longitude+step[1]*width)
+		neighbours = append(neighbours, neighbour.ToGeohash(precision))
+	}
+	return neighbours
+}
+
+/*
+GeohashCover returns, sorted, the geohashes of precision characters whose cells
+cover every point within distance nautical miles of the great circle leg from
+routeStartCoord to routeEndCoord, between its ends.
+
+The cover is conservative: some cells may be a little beyond distance, so
+points found in them should still be checked, with PointsInReach or
+FindPointsInReach. Those also find points near the great circle beyond the ends
+of the leg, which the cover does not reach. Small distances at a low precision
+give few large cells; large distances at a high precision give very many small
+ones.
+*/
+func GeohashCover(routeStartCoord, routeEndCoord Coordinate, distance float64, precision int) []string {
+	if precision < 1 {
+		precision = 1
+	}
+	if precision > GeohashMaxPrecision {
+		precision = GeohashMaxPrecision
+	}
+	// flood fill from the cell of the start of the leg, through cells that may be within reach
+	start := routeStartCoord.ToGeohash(precision)
+	seen := map[string]bool{start: true}
+	queue := []string{start}
+	var cover []string
+	for len(queue) > 0 {
+		hash := queue[0]
+		queue = queue[1:]
+		cell, _ := DecodeGeohash(hash)
+		if !cellInReach(cell, routeStartCoord, routeEndCoord, distance) {
+			continue
+		}
+		cover = append(cover, hash)
+		for _, neighbour := range geohashNeighbours(cell, precision) {
+			if !seen[neighbour] {
+				seen[neighbour] = true
+				queue = append(queue, neighbour)
+			}
+		}
+	}
+	sort.Strings(cover)
+	return cover
+}
+
+/*
+cellInReach reports whether any point of the cell may be within distance
+nautical miles of the leg: whether its centre is within distance plus the
+distance from its centre to its furthest corner.
+*/
+func cellInReach(cell Cell, routeStartCoord, routeEndCoord Coordinate, distance float64) bool {
+	south, west := cell.SouthWest.LatLonDegrees()
+	north, east := cell.NorthEast.LatLonDegrees()
+	radius := 0.0
+	for _, corner := range [][2]float64{{south, west}, {south, east}, {north, west}, {north, east}} {
+		coord, _ := FromLatLonDegrees(corner[0], corner[1])
+		radius = math.Max(radius, Distance(cell.Centre, coord))
+	}
+	nearest := segmentClosestPoint(routeStartCoord, routeEndCoord, cell.Centre)
+	return Distance(nearest, cell.Centre) <= distance+radius
+}
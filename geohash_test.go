@@ -0,0 +1,134 @@
+package greatcircle
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestToGeohash(t *testing.T) {
+	jutland, _ := FromLatLonDegrees(57.64911, 10.40744)
+	origin, _ := FromLatLonDegrees(0, 0)
+	cases := []struct {
+		coord     Coordinate
+		precision int
+		expected  string
+	}{
+		{jutland, 11, "u4pruydqqvj"},
+		{jutland, 5, "u4pru"},
+		{jutland, 0, "u"},
+		{jutland, 20, "u4pruydqqvj8"},
+		{coordKSFO.Coord, 6, "9q8vzr"},
+		{origin, 4, "s000"},
+	}
+	for _, c := range cases {
+		result := c.coord.ToGeohash(c.precision)
+		if result != c.expected {
+			t.Fatalf("Expected: %s, received %s", c.expected, result)
+		}
+	}
+}
+
+func TestDecodeGeohash(t *testing.T) {
+	cell, err := DecodeGeohash("u4pruydqqvj")
+	if err != nil {
+		t.Fatalf("Error decoding; error %v", err)
+	}
+	latitude, longitude := cell.Centre.LatLonDegrees()
+	if math.Abs(latitude-57.64911) > 1e-5 || math.Abs(longitude-10.40744) > 1e-5 {
+		t.Fatalf("Expected: 57.64911,10.40744, received %v,%v", latitude, longitude)
+	}
+	jutland, _ := FromLatLonDegrees(57.64911, 10.40744)
+	if !cell.Contains(jutland) {
+		t.Fatalf("Expected %v to contain %v", cell, jutland)
+	}
+
+	cell, _ = DecodeGeohash("S")
+	south, west := cell.SouthWest.LatLonDegrees()
+	north, east := cell.NorthEast.LatLonDegrees()
+	if south != 0 || west != 0 || north != 45 || east != 45 {
+		t.Fatalf("Expected: 0,0 to 45,45, received %v,%v to %v,%v", south, west, north, east)
+	}
+
+	for _, hash := range []string{"", "u4pa", "u4pruydqqvjqq"} {
+		if _, err := DecodeGeohash(hash); !errors.Is(err, ErrGridReference) {
+			t.Fatalf("Expected: %v, received %v from %q", ErrGridReference, err, hash)
+		}
+	}
+
+	random := rand.New(rand.NewSource(20))
+	for i := 0; i < 1000; i++ {
+		coord, _ := FromLatLonDegrees(random.Float64()*180-90, random.Float64()*360-180)
+		for precision := 1; precision <= GeohashMaxPrecision; precision++ {
+			cell, err := DecodeGeohash(coord.ToGeohash(precision))
+			if err != nil || !cell.Contains(coord) {
+				t.Fatalf("Expected %v to contain %v, %v", cell, coord, err)
+			}
+		}
+	}
+}
+
+func TestGeohashNeighbours(t *testing.T) {
+	cases := []struct {
+		hash     string
+		expected []string
+	}{
+		{"s", []string{"u", "v", "t", "m", "k", "7", "e", "g"}},
+		// across the antimeridian
+		{"8", []string{"b", "c", "9", "3", "2", "r", "x", "z"}},
+		// touching the north pole
+		{"b", []string{"c", "9", "8", "x", "z"}},
+	}
+	for _, c := range cases {
+		result, err := GeohashNeighbours(c.hash)
+		if err != nil {
+			t.Fatalf("Error finding neighbours of %s; error %v", c.hash, err)
+		}
+		if !reflect.DeepEqual(result, c.expected) {
+			t.Fatalf("Expected: %v, received %v", c.expected, result)
+		}
+	}
+}
+
+func TestGeohashCover(t *testing.T) {
+	distance := 25.0
+	cover := GeohashCover(coordKSFO.Coord, coordKLAX.Coord, distance, 4)
+	if len(cover) == 0 || len(cover) > 200 {
+		t.Fatalf("Expected a cover of around a hundred cells, received %v", cover)
+	}
+	if !sort.StringsAreSorted(cover) {
+		t.Fatalf("Expected sorted cells, received %v", cover)
+	}
+
+	// every point within reach of the leg is in a covering cell
+	random := rand.New(rand.NewSource(20))
+	var inReach []Coordinate
+	for i := 0; i < 5000; i++ {
+		poi, _ := FromLatLonDegrees(32+random.Float64()*8, -124+random.Float64()*8)
+		if Distance(segmentClosestPoint(coordKSFO.Coord, coordKLAX.Coord, poi), poi) <= distance {
+			inReach = append(inReach, poi)
+		}
+	}
+	if len(inReach) == 0 {
+		t.Fatalf("Expected points in reach")
+	}
+	for _, poi := range inReach {
+		hash := poi.ToGeohash(4)
+		i := sort.SearchStrings(cover, hash)
+		if i == len(cover) || cover[i] != hash {
+			t.Fatalf("Expected %s for %v in %v", hash, poi, cover)
+		}
+	}
+
+	// and the cover is a prefix of the finer cover
+	for _, hash := range GeohashCover(coordKSFO.Coord, coordKLAX.Coord, distance, 5) {
+		i := sort.SearchStrings(cover, hash[:4])
+		if i == len(cover) || !strings.HasPrefix(hash, cover[i]) {
+			t.Fatalf("Expected %s to be within %v", hash, cover)
+		}
+	}
+}
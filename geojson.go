@@ -0,0 +1,353 @@
+package greatcircle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+/*
+GeoJSON (RFC 7946) positions are [longitude, latitude] in decimal degrees with
+East positive; this library uses radians with West positive. The functions
+below convert between the two.
+*/
+
+// ErrGeoJSONType is returned when GeoJSON does not contain the expected geometry.
+var ErrGeoJSONType = errors.New("unexpected GeoJSON type")
+
+type geoJSONGeometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+type geoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   *geoJSONGeometry       `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+// geoJSONPosition converts a Coordinate into a GeoJSON [longitude, latitude]
+func geoJSONPosition(coord Coordinate) [2]float64 {
+	latitude, longitude := coord.LatLonDegrees()
+	return [2]float64{longitude, latitude}
+}
+
+// geoJSONCoordinate converts a GeoJSON [longitude, latitude] into a Coordinate
+func geoJSONCoordinate(position []float64) (Coordinate, error) {
+	if len(position) < 2 {
+		return Coordinate{}, fmt.Errorf("greatcircle: GeoJSON position %v: %w", position, ErrGeoJSONType)
+	}
+	return FromLatLonDegrees(position[1], position[0])
+}
+
+func geoJSONPoint(coord Coordinate, properties map[string]interface{}) geoJSONFeature {
+	coordinates, _ := json.Marshal(geoJSONPosition(coord))
+	return geoJSONFeature{"Feature", &geoJSONGeometry{"Point", coordinates}, properties}
+}
+
+/*
+geoJSONLineString is a LineString Feature through coords or, if it crosses the
+antimeridian, a MultiLineString split there as RFC 7946 recommends.
+*/
+func geoJSONLineString(coords []Coordinate, properties map[string]interface{}) geoJSONFeature {
+	parts := splitAntimeridian(coords)
+	lines := make([][][2]float64, len(parts))
+	for i, part := range parts {
+		lines[i] = make([][2]float64, len(part))
+		for j, coord := range part {
+			lines[i][j] = geoJSONPosition(coord)
+		}
+	}
+	if len(lines) > 1 {
+		coordinates, _ := json.Marshal(lines)
+		return geoJSONFeature{"Feature", &geoJSONGeometry{"MultiLineString", coordinates}, properties}
+	}
+	positions := [][2]float64{}
+	if len(lines) == 1 {
+		positions = lines[0]
+	}
+	coordinates, _ := json.Marshal(positions)
+	return geoJSONFeature{"Feature", &geoJSONGeometry{"LineString", coordinates}, properties}
+}
+
+/*
+ToGeoJSON encodes the Coordinate as a GeoJSON Point Feature.
+*/
+func (coord Coordinate) ToGeoJSON() ([]byte, error) {
+	return json.Marshal(geoJSONPoint(coord, map[string]interface{}{}))
+}
+
+/*
+ToGeoJSON encodes the NamedCoordinate as a GeoJSON Point Feature
+with a "name" property, left out when it has no name as in KML and GPX.
+*/
+func (coord NamedCoordinate) ToGeoJSON() ([]byte, error) {
+	return json.Marshal(geoJSONPoint(coord.Coord, geoJSONNameProperties(coord.Name)))
+}
+
+// geoJSONNameProperties are the properties of a named point, without "name" if it has none
+func geoJSONNameProperties(name string) map[string]interface{} {
+	if name == "" {
+		return map[string]interface{}{}
+	}
+	return map[string]interface{}{"name": name}
+}
+
+/*
+ToGeoJSON encodes the route as LegTypedRoute's ToGeoJSON does, with every leg a
+great circle.
+*/
+func (route MultiPointRoute) ToGeoJSON() ([]byte, error) {
+	return route.WithLegTypes().ToGeoJSON()
+}
+
+/*
+ToGeoJSON encodes the route as a GeoJSON LineString Feature, or a
+MultiLineString split where a leg crosses the antimeridian.
+
+The waypoint names are kept in the "names" property, and how each leg is
+travelled in the "legs" property.
+*/
+func (route LegTypedRoute) ToGeoJSON() ([]byte, error) {
+	coords := make([]Coordinate, len(route.Points))
+	names := make([]string, len(route.Points))
+	legs := []LegType{}
+	for i, coord := range route.Points {
+		coords[i] = coord.Coord
+		names[i] = coord.Name
+		if i > 0 {
+			legs = append(legs, route.LegType(i-1))
+		}
+	}
+	return json.Marshal(geoJSONLineString(coords, map[string]interface{}{"names": names, "legs": legs}))
+}
+
+/*
+NamedCoordinatesToGeoJSON encodes a list of points of interest as a GeoJSON
+FeatureCollection of Point Features with "name" properties, for those that
+have a name.
+*/
+func NamedCoordinatesToGeoJSON(coords []NamedCoordinate) ([]byte, error) {
+	collection := geoJSONFeatureCollection{"FeatureCollection", []geoJSONFeature{}}
+	for _, coord := range coords {
+		collection.Features = append(collection.Features, geoJSONPoint(coord.Coord, geoJSONNameProperties(coord.Name)))
+	}
+	return json.Marshal(collection)
+}
+
+/*
+MultiPointsToGeoJSON encodes the results of MultiPointRoutePOIS as a GeoJSON
+FeatureCollection.
+
+Each point of interest is a Point Feature whose "nearest" property is the position
+of the nearest point on the route, and "distance" the distance to it in nautical miles.
+Each is followed by a LineString Feature linking the point of interest to the route.
+*/
+func MultiPointsToGeoJSON(results []MultiPoint) ([]byte, error) {
+	collection := geoJSONFeatureCollection{"FeatureCollection", []geoJSONFeature{}}
+	for _, result := range results {
+		collection.Features = append(collection.Features,
+			geoJSONPoint(result.Poi, map[string]interface{}{
+				"nearest":  geoJSONPosition(result.Neareast),
+				"distance": result.Distance,
+			}),
+			geoJSONLineString([]Coordinate{result.Poi, result.Neareast}, map[string]interface{}{
+				"distance": result.Distance,
+			}))
+	}
+	return json.Marshal(collection)
+}
+
+/*
+geoJSONGeometryOf returns the geometry of a GeoJSON Feature, or the
+GeoJSON itself if it is a bare geometry, along with the Feature properties.
+*/
+func geoJSONGeometryOf(data []byte) (*geoJSONGeometry, map[string]interface{}, error) {
+	var feature geoJSONFeature
+	if err := json.Unmarshal(data, &feature); err != nil {
+		return nil, nil, err
+	}
+	if feature.Type != "Feature" {
+		var geometry geoJSONGeometry
+		if err := json.Unmarshal(data, &geometry); err != nil {
+			return nil, nil, err
+		}
+		return &geometry, map[string]interface{}{}, nil
+	}
+	if feature.Geometry == nil {
+		return nil, nil, fmt.Errorf("greatcircle: GeoJSON Feature without geometry: %w", ErrGeoJSONType)
+	}
+	return feature.Geometry, feature.Properties, nil
+}
+
+func parseGeoJSONPoint(geometry *geoJSONGeometry, properties map[string]interface{}) (NamedCoordinate, error) {
+	if geometry.Type != "Point" {
+		return NamedCoordinate{}, fmt.Errorf("greatcircle: GeoJSON %s is not a Point: %w", geometry.Type, ErrGeoJSONType)
+	}
+	var position []float64
+	if err := json.Unmarshal(geometry.Coordinates, &position); err != nil {
+		return NamedCoordinate{}, err
+	}
+	coord, err := geoJSONCoordinate(position)
+	if err != nil {
+		return NamedCoordinate{}, err
+	}
+	name, _ := properties["name"].(string)
+	return NamedCoordinate{coord, name}, nil
+}
+
+/*
+ParseGeoJSONCoordinate decodes a GeoJSON Point, or a Point Feature, into a
+NamedCoordinate. The name is taken from the "name" property, if any.
+*/
+func ParseGeoJSONCoordinate(data []byte) (NamedCoordinate, error) {
+	geometry, properties, err := geoJSONGeometryOf(data)
+	if err != nil {
+		return NamedCoordinate{}, err
+	}
+	return parseGeoJSONPoint(geometry, properties)
+}
+
+/*
+ParseGeoJSONRoute decodes a GeoJSON LineString, or a LineString Feature, into a
+LegTypedRoute. Waypoint names and leg types are restored from the "names" and
+"legs" properties written by ToGeoJSON; legs without one are great circles. A
+MultiLineString split at the antimeridian is joined again.
+*/
+func ParseGeoJSONRoute(data []byte) (LegTypedRoute, error) {
+	geometry, _, err := geoJSONGeometryOf(data)
+	if err != nil {
+		return LegTypedRoute{}, err
+	}
+	var positions [][]float64
+	switch geometry.Type {
+	case "LineString":
+		if err := json.Unmarshal(geometry.Coordinates, &positions); err != nil {
+			return LegTypedRoute{}, err
+		}
+	case "MultiLineString":
+		var lines [][][]float64
+		if err := json.Unmarshal(geometry.Coordinates, &lines); err != nil {
+			return LegTypedRoute{}, err
+		}
+		positions = joinAntimeridian(lines)
+	default:
+		return LegTypedRoute{}, fmt.Errorf("greatcircle: GeoJSON %s is not a LineString: %w", geometry.Type, ErrGeoJSONType)
+	}
+	var feature struct {
+		Properties struct {
+			Names []string  `json:"names"`
+			Legs  []LegType `json:"legs"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(data, &feature); err != nil {
+		return LegTypedRoute{}, err
+	}
+
+	route := LegTypedRoute{}
+	for i, position := range positions {
+		coord, err := geoJSONCoordinate(position)
+		if err != nil {
+			return LegTypedRoute{}, err
+		}
+		namedCoord := coord.ToNamedCoordinate()
+		if i < len(feature.Properties.Names) {
+			namedCoord.Name = feature.Properties.Names[i]
+		}
+		route.Points = append(route.Points, namedCoord)
+		if i > 0 && i-1 < len(feature.Properties.Legs) {
+			route.SetLegType(i-1, feature.Properties.Legs[i-1])
+		}
+	}
+	return route, nil
+}
+
+/*
+joinAntimeridian joins lines split at the antimeridian back into one, leaving
+out the positions added on the antimeridian where they were split.
+*/
+func joinAntimeridian(lines [][][]float64) [][]float64 {
+	onAntimeridian := func(position []float64) bool {
+		return len(position) >= 2 && math.Abs(position[0]) == 180
+	}
+	var positions [][]float64
+	for i, line := range lines {
+		if i > 0 && len(line) > 0 && onAntimeridian(line[0]) && len(positions) > 0 && onAntimeridian(positions[len(positions)-1]) {
+			positions = positions[:len(positions)-1]
+			line = line[1:]
+		}
+		positions = append(positions, line...)
+	}
+	return positions
+}
+
+/*
+ParseGeoJSONNamedCoordinates decodes the Point Features of a GeoJSON
+FeatureCollection into NamedCoordinates. Other Features are ignored.
+*/
+func ParseGeoJSONNamedCoordinates(data []byte) ([]NamedCoordinate, error) {
+	var collection geoJSONFeatureCollection
+	if err := json.Unmarshal(data, &collection); err != nil {
+		return nil, err
+	}
+	if collection.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("greatcircle: GeoJSON %s is not a FeatureCollection: %w", collection.Type, ErrGeoJSONType)
+	}
+	coords := []NamedCoordinate{}
+	for _, feature := range collection.Features {
+		if feature.Geometry == nil || feature.Geometry.Type != "Point" {
+			continue
+		}
+		coord, err := parseGeoJSONPoint(feature.Geometry, feature.Properties)
+		if err != nil {
+			return nil, err
+		}
+		coords = append(coords, coord)
+	}
+	return coords, nil
+}
+
+/*
+ParseGeoJSONMultiPoints decodes a GeoJSON FeatureCollection written by
+MultiPointsToGeoJSON back into MultiPoint results.
+*/
+func ParseGeoJSONMultiPoints(data []byte) ([]MultiPoint, error) {
+	var collection struct {
+		Type     string `json:"type"`
+		Features []struct {
+			Geometry   *geoJSONGeometry `json:"geometry"`
+			Properties struct {
+				Nearest  []float64 `json:"nearest"`
+				Distance float64   `json:"distance"`
+			} `json:"properties"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(data, &collection); err != nil {
+		return nil, err
+	}
+	if collection.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("greatcircle: GeoJSON %s is not a FeatureCollection: %w", collection.Type, ErrGeoJSONType)
+	}
+	results := []MultiPoint{}
+	for _, feature := range collection.Features {
+		if feature.Geometry == nil || feature.Geometry.Type != "Point" {
+			continue
+		}
+		poi, err := parseGeoJSONPoint(feature.Geometry, nil)
+		if err != nil {
+			return nil, err
+		}
+		nearest, err := geoJSONCoordinate(feature.Properties.Nearest)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, MultiPoint{poi.Coord, nearest, feature.Properties.Distance})
+	}
+	return results, nil
+}
@@ -0,0 +1,157 @@
+package greatcircle
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestCoordinateToGeoJSON(t *testing.T) {
+	result, err := Coordinate{DegreesToRadians(37.5), DegreesToRadians(122.25)}.ToGeoJSON()
+	if err != nil {
+		t.Fatalf("Error encoding GeoJSON; error %v", err)
+	}
+	expected := `{"type":"Feature","geometry":{"type":"Point","coordinates":[-122.25,37.5]},"properties":{}}`
+	if string(result) != expected {
+		t.Fatalf("Expected: %s, received %s", expected, result)
+	}
+}
+
+func TestNamedCoordinateGeoJSON(t *testing.T) {
+	data, err := coordKSFO.ToGeoJSON()
+	if err != nil {
+		t.Fatalf("Error encoding GeoJSON; error %v", err)
+	}
+	if !strings.Contains(string(data), `"name":"KSFO"`) {
+		t.Fatalf("Expected a name property, received %s", data)
+	}
+	result, err := ParseGeoJSONCoordinate(data)
+	if err != nil {
+		t.Fatalf("Error parsing GeoJSON; error %v", err)
+	}
+	if !result.Coord.Equal(coordKSFO.Coord) || result.Name != "KSFO" {
+		t.Fatalf("Expected: %v, received %v", coordKSFO, result)
+	}
+
+	// a bare geometry, East positive
+	result, err = ParseGeoJSONCoordinate([]byte(`{"type":"Point","coordinates":[151.177,-33.946]}`))
+	if err != nil {
+		t.Fatalf("Error parsing GeoJSON; error %v", err)
+	}
+	if math.Abs(RadiansToDegrees(result.Coord.Longitude)+151.177) > 1e-9 || math.Abs(RadiansToDegrees(result.Coord.Latitude)+33.946) > 1e-9 {
+		t.Fatalf("Expected West positive coordinate, received %v", result)
+	}
+
+	_, err = ParseGeoJSONCoordinate([]byte(`{"type":"LineString","coordinates":[[0,0],[1,1]]}`))
+	if !errors.Is(err, ErrGeoJSONType) {
+		t.Fatalf("Expected: %v, received %v", ErrGeoJSONType, err)
+	}
+	_, err = ParseGeoJSONCoordinate([]byte(`{"type":"Point","coordinates":[0,95]}`))
+	if !errors.Is(err, ErrInvalidLatitude) {
+		t.Fatalf("Expected: %v, received %v", ErrInvalidLatitude, err)
+	}
+}
+
+func TestMultiPointRouteGeoJSON(t *testing.T) {
+	route := NewMultiPointRoute([]NamedCoordinate{coordKSFO, coordKLAX, coordKJFK}).WithLegTypes(GreatCircleLeg, RhumbLineLeg)
+	data, err := route.ToGeoJSON()
+	if err != nil {
+		t.Fatalf("Error encoding GeoJSON; error %v", err)
+	}
+	if !strings.Contains(string(data), `"type":"LineString"`) {
+		t.Fatalf("Expected a LineString, received %s", data)
+	}
+	result, err := ParseGeoJSONRoute(data)
+	if err != nil {
+		t.Fatalf("Error parsing GeoJSON; error %v", err)
+	}
+	if len(result.Points) != len(route.Points) {
+		t.Fatalf("Expected %v points, received %v", len(route.Points), len(result.Points))
+	}
+	for i := range route.Points {
+		if !result.Points[i].Coord.Equal(route.Points[i].Coord) || result.Points[i].Name != route.Points[i].Name {
+			t.Fatalf("Expected: %v, received %v", route.Points[i], result.Points[i])
+		}
+	}
+	for leg := 0; leg < 2; leg++ {
+		if result.LegType(leg) != route.LegType(leg) {
+			t.Fatalf("Expected: %v, received %v", route.LegType(leg), result.LegType(leg))
+		}
+	}
+}
+
+func TestMultiPointRouteGeoJSONAntimeridian(t *testing.T) {
+	tokyo, _ := FromLatLonDegrees(35.55, 139.78)
+	route := NewMultiPointRoute([]NamedCoordinate{{tokyo, "RJTT"}, coordKSFO})
+	data, err := route.ToGeoJSON()
+	if err != nil {
+		t.Fatalf("Error encoding GeoJSON; error %v", err)
+	}
+	if !strings.Contains(string(data), `"type":"MultiLineString"`) {
+		t.Fatalf("Expected a MultiLineString, received %s", data)
+	}
+	result, err := ParseGeoJSONRoute(data)
+	if err != nil {
+		t.Fatalf("Error parsing GeoJSON; error %v", err)
+	}
+	if len(result.Points) != len(route) {
+		t.Fatalf("Expected %v points, received %v", len(route), len(result.Points))
+	}
+	for i := range route {
+		if !result.Points[i].Coord.Equal(route[i].Coord) || result.Points[i].Name != route[i].Name {
+			t.Fatalf("Expected: %v, received %v", route[i], result.Points[i])
+		}
+	}
+}
+
+func TestNamedCoordinatesGeoJSON(t *testing.T) {
+	coords := []NamedCoordinate{coordKMOD, coordKMAE, coordKSJC.Coord.ToNamedCoordinate()}
+	data, err := NamedCoordinatesToGeoJSON(coords)
+	if err != nil {
+		t.Fatalf("Error encoding GeoJSON; error %v", err)
+	}
+	// an unnamed point has no name property, as in KML and GPX
+	if strings.Count(string(data), `"name"`) != 2 || strings.Contains(string(data), `"name":""`) {
+		t.Fatalf("Expected names for the named points only, received %s", data)
+	}
+	result, err := ParseGeoJSONNamedCoordinates(data)
+	if err != nil {
+		t.Fatalf("Error parsing GeoJSON; error %v", err)
+	}
+	if len(result) != 3 || result[0].Name != "KMOD" || !result[1].Coord.Equal(coordKMAE.Coord) || result[2].Name != "" {
+		t.Fatalf("Expected: %v, received %v", coords, result)
+	}
+
+	data, _ = coordKSJC.Coord.ToNamedCoordinate().ToGeoJSON()
+	if strings.Contains(string(data), `"name"`) {
+		t.Fatalf("Expected no name property, received %s", data)
+	}
+}
+
+func TestMultiPointsGeoJSON(t *testing.T) {
+	route := []Coordinate{coordKSFO.Coord, coordKLAX.Coord}
+	results := MultiPointRoutePOIS(route, []Coordinate{coordKSJC.Coord, coordKMAE.Coord}, 50)
+	if len(results) == 0 {
+		t.Fatalf("Expected points of interest near the route")
+	}
+	data, err := MultiPointsToGeoJSON(results)
+	if err != nil {
+		t.Fatalf("Error encoding GeoJSON; error %v", err)
+	}
+	if strings.Count(string(data), `"LineString"`) != len(results) {
+		t.Fatalf("Expected each POI to be linked to the route, received %s", data)
+	}
+	parsed, err := ParseGeoJSONMultiPoints(data)
+	if err != nil {
+		t.Fatalf("Error parsing GeoJSON; error %v", err)
+	}
+	if len(parsed) != len(results) {
+		t.Fatalf("Expected %v results, received %v", len(results), len(parsed))
+	}
+	for i := range results {
+		if !parsed[i].Poi.Equal(results[i].Poi) || !parsed[i].Neareast.Equal(results[i].Neareast) || parsed[i].Distance != results[i].Distance {
+			t.Fatalf("Expected: %v, received %v", results[i], parsed[i])
+		}
+	}
+}
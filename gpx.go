@@ -0,0 +1,309 @@
+package greatcircle
+
+import (
+	"encoding/xml"
+	"io"
+	"math"
+	"time"
+)
+
+/*
+GPX is the content of a GPS Exchange Format (GPX 1.1) file, as exchanged
+by Garmin, ForeFlight and most other navigation software.
+
+GPX positions are decimal degrees with East positive; they are converted to
+and from this library's radians with West positive.
+*/
+type GPX struct {
+	Creator   string
+	Waypoints []GPXPoint
+	Routes    []GPXRoute
+	Tracks    []GPXTrack
+}
+
+/*
+GPXPoint is a waypoint, route point or track point.
+*/
+type GPXPoint struct {
+	NamedCoordinate
+	// Elevation is in metres, or nil if not recorded
+	Elevation *float64
+	// Time is the zero time if not recorded
+	Time time.Time
+}
+
+/*
+GPXRoute is a named, ordered list of points describing a planned route.
+*/
+type GPXRoute struct {
+	Name   string
+	Points []GPXPoint
+}
+
+/*
+GPXTrack is a recorded track, made up of one or more continuous segments.
+*/
+type GPXTrack struct {
+	Name     string
+	Segments []GPXTrackSegment
+}
+
+/*
+GPXTrackSegment is a continuous, time-stamped sequence of track points.
+*/
+type GPXTrackSegment []GPXPoint
+
+const gpxNamespace = "http://www.topografix.com/GPX/1/1"
+
+type gpxFile struct {
+	XMLName   xml.Name      `xml:"gpx"`
+	Namespace string        `xml:"xmlns,attr"`
+	Version   string        `xml:"version,attr"`
+	Creator   string        `xml:"creator,attr"`
+	Waypoints []gpxPointXML `xml:"wpt"`
+	Routes    []gpxRouteXML `xml:"rte"`
+	Tracks    []gpxTrackXML `xml:"trk"`
+}
+
+type gpxPointXML struct {
+	Latitude  float64    `xml:"lat,attr"`
+	Longitude float64    `xml:"lon,attr"`
+	Elevation *float64   `xml:"ele,omitempty"`
+	Time      *time.Time `xml:"time,omitempty"`
+	Name      string     `xml:"name,omitempty"`
+}
+
+type gpxRouteXML struct {
+	Name   string        `xml:"name,omitempty"`
+	Points []gpxPointXML `xml:"rtept"`
+}
+
+type gpxTrackXML struct {
+	Name     string               `xml:"name,omitempty"`
+	Segments []gpxTrackSegmentXML `xml:"trkseg"`
+}
+
+type gpxTrackSegmentXML struct {
+	Points []gpxPointXML `xml:"trkpt"`
+}
+
+/*
+ReadGPX reads the waypoints, routes and tracks of a GPX file.
+*/
+func ReadGPX(r io.Reader) (*GPX, error) {
+	var file gpxFile
+	if err := xml.NewDecoder(r).Decode(&file); err != nil {
+		return nil, err
+	}
+	gpx := &GPX{Creator: file.Creator}
+	var err error
+	if gpx.Waypoints, err = gpxPoints(file.Waypoints); err != nil {
+		return nil, err
+	}
+	for _, rte := range file.Routes {
+		route := GPXRoute{Name: rte.Name}
+		if route.Points, err = gpxPoints(rte.Points); err != nil {
+			return nil, err
+		}
+		gpx.Routes = append(gpx.Routes, route)
+	}
+	for _, trk := range file.Tracks {
+		track := GPXTrack{Name: trk.Name}
+		for _, trkseg := range trk.Segments {
+			segment, err := gpxPoints(trkseg.Points)
+			if err != nil {
+				return nil, err
+			}
+			track.Segments = append(track.Segments, segment)
+		}
+		gpx.Tracks = append(gpx.Tracks, track)
+	}
+	return gpx, nil
+}
+
+/*
+Write writes the GPX as a GPX 1.1 document.
+*/
+func (gpx *GPX) Write(w io.Writer) error {
+	file := gpxFile{
+		Namespace: gpxNamespace,
+		Version:   "1.1",
+		Creator:   gpx.Creator,
+		Waypoints: gpxPointsXML(gpx.Waypoints),
+	}
+	if file.Creator == "" {
+		file.Creator = "github.com/drnic/go-greatcircle"
+	}
+	for _, route := range gpx.Routes {
+		file.Routes = append(file.Routes, gpxRouteXML{route.Name, gpxPointsXML(route.Points)})
+	}
+	for _, track := range gpx.Tracks {
+		trk := gpxTrackXML{Name: track.Name}
+		for _, segment := range track.Segments {
+			trk.Segments = append(trk.Segments, gpxTrackSegmentXML{gpxPointsXML(segment)})
+		}
+		file.Tracks = append(file.Tracks, trk)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(file); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+/*
+NamedCoordinates returns the waypoints of the GPX, for example as a list
+of points of interest.
+*/
+func (gpx *GPX) NamedCoordinates() []NamedCoordinate {
+	coords := make([]NamedCoordinate, len(gpx.Waypoints))
+	for i, waypoint := range gpx.Waypoints {
+		coords[i] = waypoint.NamedCoordinate
+	}
+	return coords
+}
+
+/*
+NewGPXRoute creates a GPXRoute from a MultiPointRoute. A GPX route holds only
+the waypoints, which software joins with straight lines; NewGPXTrack draws the
+legs themselves.
+*/
+func NewGPXRoute(name string, route MultiPointRoute) GPXRoute {
+	points := make([]GPXPoint, len(route))
+	for i, coord := range route {
+		points[i] = GPXPoint{NamedCoordinate: coord}
+	}
+	return GPXRoute{name, points}
+}
+
+/*
+NewGPXTrack creates a GPXTrack drawing each leg of a LegTypedRoute with
+intermediate points no more than spacing nautical miles apart, following its
+LegType; zero spacing draws only the waypoints. The track is split into a new
+segment where it crosses the antimeridian, so that it is not drawn across the
+whole map.
+*/
+func NewGPXTrack(name string, route LegTypedRoute, spacing float64) GPXTrack {
+	var points []Coordinate
+	for leg := 0; leg+1 < len(route.Points); leg++ {
+		densified := route.DensifyLeg(leg, spacing)
+		if leg > 0 {
+			densified = densified[1:]
+		}
+		points = append(points, densified...)
+	}
+	if len(route.Points) == 1 {
+		points = []Coordinate{route.Points[0].Coord}
+	}
+	track := GPXTrack{Name: name}
+	for _, part := range splitAntimeridian(points) {
+		segment := make(GPXTrackSegment, len(part))
+		for i, point := range part {
+			segment[i] = GPXPoint{NamedCoordinate: point.ToNamedCoordinate()}
+		}
+		track.Segments = append(track.Segments, segment)
+	}
+	return track
+}
+
+/*
+MultiPointRoute returns the route points as a MultiPointRoute.
+*/
+func (route GPXRoute) MultiPointRoute() MultiPointRoute {
+	coords := make([]NamedCoordinate, len(route.Points))
+	for i, point := range route.Points {
+		coords[i] = point.NamedCoordinate
+	}
+	return NewMultiPointRoute(coords)
+}
+
+/*
+TrackDeviation is how far a recorded track point was from the planned route.
+*/
+type TrackDeviation struct {
+	Point GPXPoint
+	// Leg is the index of the route leg nearest to the point
+	Leg int
+	// CrossTrack is the distance off course in nautical miles;
+	// positive is right of course, negative left
+	CrossTrack float64
+}
+
+/*
+Deviations compares each point of a flown track segment against the planned
+route, returning its cross track error from the nearest leg of the route. A
+MultiPointRoute is compared as route.WithLegTypes().
+*/
+func (segment GPXTrackSegment) Deviations(route LegTypedRoute) []TrackDeviation {
+	deviations := make([]TrackDeviation, 0, len(segment))
+	if len(route.Points) < 2 {
+		return deviations
+	}
+	for _, point := range segment {
+		deviation := TrackDeviation{Point: point}
+		nearest := math.Inf(1)
+		for leg := 0; leg+1 < len(route.Points); leg++ {
+			from, to := route.Points[leg].Coord, route.Points[leg+1].Coord
+			// the nearest point on the leg itself, not its continuation
+			var closest Coordinate
+			if route.LegType(leg) == RhumbLineLeg {
+				closest = RhumbClosestPoint(from, to, point.Coord)
+			} else {
+				closest = segmentClosestPoint(from, to, point.Coord)
+			}
+			distance := Distance(closest, point.Coord)
+			if distance < nearest {
+				nearest = distance
+				deviation.Leg = leg
+				if route.LegType(leg) == RhumbLineLeg {
+					deviation.CrossTrack = math.Copysign(distance, CrossTrackError(from, to, point.Coord))
+				} else {
+					deviation.CrossTrack = RadiansToNM(CrossTrackError(from, to, point.Coord))
+				}
+			}
+		}
+		deviations = append(deviations, deviation)
+	}
+	return deviations
+}
+
+func gpxPoints(points []gpxPointXML) ([]GPXPoint, error) {
+	var result []GPXPoint
+	for _, point := range points {
+		coord, err := FromLatLonDegrees(point.Latitude, point.Longitude)
+		if err != nil {
+			return nil, err
+		}
+		gpxPoint := GPXPoint{NamedCoordinate: NamedCoordinate{coord, point.Name}, Elevation: point.Elevation}
+		if point.Time != nil {
+			gpxPoint.Time = *point.Time
+		}
+		result = append(result, gpxPoint)
+	}
+	return result, nil
+}
+
+func gpxPointsXML(points []GPXPoint) []gpxPointXML {
+	var result []gpxPointXML
+	for _, point := range points {
+		latitude, longitude := point.Coord.LatLonDegrees()
+		pointXML := gpxPointXML{
+			Latitude:  latitude,
+			Longitude: longitude,
+			Elevation: point.Elevation,
+			Name:      point.Name,
+		}
+		if !point.Time.IsZero() {
+			pointTime := point.Time
+			pointXML.Time = &pointTime
+		}
+		result = append(result, pointXML)
+	}
+	return result
+}
@@ -0,0 +1,161 @@
+package greatcircle
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+var sampleGPX = `<?xml version="1.0" encoding="UTF-8"?>
+<gpx version="1.1" creator="ForeFlight" xmlns="http://www.topografix.com/GPX/1/1">
+  <wpt lat="37.618889" lon="-122.375">
+    <ele>4</ele>
+    <name>KSFO</name>
+  </wpt>
+  <wpt lat="33.9425" lon="-118.408056">
+    <ele>38.1</ele>
+    <name>KLAX</name>
+  </wpt>
+  <rte>
+    <name>KSFO-KLAX</name>
+    <rtept lat="37.618889" lon="-122.375"><name>KSFO</name></rtept>
+    <rtept lat="36.59" lon="-121.85"><name>SNS</name></rtept>
+    <rtept lat="33.9425" lon="-118.408056"><name>KLAX</name></rtept>
+  </rte>
+  <trk>
+    <name>Flown</name>
+    <trkseg>
+      <trkpt lat="37.618889" lon="-122.375"><ele>4</ele><time>2024-05-01T16:00:00Z</time></trkpt>
+      <trkpt lat="37.2" lon="-122.0"><ele>1500</ele><time>2024-05-01T16:10:00Z</time></trkpt>
+      <trkpt lat="35.0" lon="-120.0"><ele>2500</ele><time>2024-05-01T16:45:00Z</time></trkpt>
+    </trkseg>
+  </trk>
+</gpx>
+`
+
+func TestReadGPX(t *testing.T) {
+	gpx, err := ReadGPX(strings.NewReader(sampleGPX))
+	if err != nil {
+		t.Fatalf("Error reading GPX; error %v", err)
+	}
+	if gpx.Creator != "ForeFlight" || len(gpx.Waypoints) != 2 || len(gpx.Routes) != 1 || len(gpx.Tracks) != 1 {
+		t.Fatalf("Expected 2 waypoints, a route and a track, received %v", gpx)
+	}
+
+	ksfo := gpx.NamedCoordinates()[0]
+	if ksfo.Name != "KSFO" || Distance(ksfo.Coord, coordKSFO.Coord) > 1 {
+		t.Fatalf("Expected: %v, received %v", coordKSFO, ksfo)
+	}
+	if ksfo.Coord.Longitude < 0 {
+		t.Fatalf("Expected West longitude to be positive, received %v", ksfo.Coord.Longitude)
+	}
+	if gpx.Waypoints[1].Elevation == nil || *gpx.Waypoints[1].Elevation != 38.1 {
+		t.Fatalf("Expected elevation 38.1, received %v", gpx.Waypoints[1].Elevation)
+	}
+
+	route := gpx.Routes[0].MultiPointRoute()
+	if len(route) != 3 || route[1].Name != "SNS" {
+		t.Fatalf("Expected a 3 point route via SNS, received %v", route)
+	}
+	if gpx.Routes[0].Points[1].Elevation != nil {
+		t.Fatalf("Expected no elevation, received %v", *gpx.Routes[0].Points[1].Elevation)
+	}
+
+	segment := gpx.Tracks[0].Segments[0]
+	expected := time.Date(2024, 5, 1, 16, 10, 0, 0, time.UTC)
+	if len(segment) != 3 || !segment[1].Time.Equal(expected) {
+		t.Fatalf("Expected a time stamped track, received %v", segment)
+	}
+}
+
+func TestWriteGPX(t *testing.T) {
+	gpx, err := ReadGPX(strings.NewReader(sampleGPX))
+	if err != nil {
+		t.Fatalf("Error reading GPX; error %v", err)
+	}
+	gpx.Routes = append(gpx.Routes, NewGPXRoute("KSFO-KJFK", NewMultiPointRoute([]NamedCoordinate{coordKSFO, coordKJFK})))
+
+	var buffer bytes.Buffer
+	if err := gpx.Write(&buffer); err != nil {
+		t.Fatalf("Error writing GPX; error %v", err)
+	}
+	if !strings.Contains(buffer.String(), `<wpt lat="37.618889" lon="-122.375">`) {
+		t.Fatalf("Expected East positive degrees, received %s", buffer.String())
+	}
+
+	result, err := ReadGPX(&buffer)
+	if err != nil {
+		t.Fatalf("Error reading GPX; error %v", err)
+	}
+	if len(result.Routes) != 2 || result.Routes[1].Name != "KSFO-KJFK" || !result.Routes[1].Points[1].Coord.Equal(coordKJFK.Coord) {
+		t.Fatalf("Expected the new route, received %v", result.Routes)
+	}
+	for i, point := range gpx.Tracks[0].Segments[0] {
+		resultPoint := result.Tracks[0].Segments[0][i]
+		if math.Abs(resultPoint.Coord.Latitude-point.Coord.Latitude) > 1e-12 || !resultPoint.Time.Equal(point.Time) ||
+			*resultPoint.Elevation != *point.Elevation {
+			t.Fatalf("Expected: %v, received %v", point, resultPoint)
+		}
+	}
+	if result.Waypoints[0].Name != "KSFO" || *result.Waypoints[0].Elevation != 4 || !result.Waypoints[0].Time.IsZero() {
+		t.Fatalf("Expected: %v, received %v", gpx.Waypoints[0], result.Waypoints[0])
+	}
+}
+
+func TestNewGPXTrack(t *testing.T) {
+	route := NewMultiPointRoute([]NamedCoordinate{coordKSFO, coordKLAX, coordKJFK}).WithLegTypes(GreatCircleLeg, RhumbLineLeg)
+	track := NewGPXTrack("KSFO-KJFK", route, 50)
+	if track.Name != "KSFO-KJFK" || len(track.Segments) != 1 {
+		t.Fatalf("Expected a segment, received %v", track)
+	}
+	segment := track.Segments[0]
+	expected := len(route.DensifyLeg(0, 50)) + len(route.DensifyLeg(1, 50)) - 1
+	if len(segment) != expected || segment[0].Coord != coordKSFO.Coord || segment[len(segment)-1].Coord != coordKJFK.Coord {
+		t.Fatalf("Expected %v points from KSFO to KJFK, received %v", expected, segment)
+	}
+
+	// across the Pacific the track is split at the antimeridian
+	tokyo, _ := FromLatLonDegrees(35.55, 139.78)
+	gpx := &GPX{Tracks: []GPXTrack{NewGPXTrack("RJTT-KSFO", NewMultiPointRoute([]NamedCoordinate{{tokyo, "RJTT"}, coordKSFO}).WithLegTypes(), 100)}}
+	if len(gpx.Tracks[0].Segments) != 2 {
+		t.Fatalf("Expected 2 segments, received %v", gpx.Tracks[0].Segments)
+	}
+	var buffer bytes.Buffer
+	if err := gpx.Write(&buffer); err != nil {
+		t.Fatalf("Error writing GPX; error %v", err)
+	}
+	if !strings.Contains(buffer.String(), `lon="180"`) || !strings.Contains(buffer.String(), `lon="-180"`) {
+		t.Fatalf("Expected segments meeting at 180 and -180, received %s", buffer.String())
+	}
+	if track := NewGPXTrack("KSFO", NewMultiPointRoute([]NamedCoordinate{coordKSFO}).WithLegTypes(), 100); len(track.Segments) != 1 || len(track.Segments[0]) != 1 {
+		t.Fatalf("Expected a single point, received %v", track)
+	}
+}
+
+func TestGPXTrackDeviations(t *testing.T) {
+	gpx, err := ReadGPX(strings.NewReader(sampleGPX))
+	if err != nil {
+		t.Fatalf("Error reading GPX; error %v", err)
+	}
+	route := gpx.Routes[0].MultiPointRoute()
+	deviations := gpx.Tracks[0].Segments[0].Deviations(route.WithLegTypes())
+	if len(deviations) != 3 {
+		t.Fatalf("Expected 3 deviations, received %v", deviations)
+	}
+	if math.Abs(deviations[0].CrossTrack) > 0.01 || deviations[0].Leg != 0 {
+		t.Fatalf("Expected to start on course, received %v", deviations[0])
+	}
+	// east of the first leg, then west of the second, both heading south east
+	if deviations[1].Leg != 0 || deviations[1].CrossTrack >= 0 {
+		t.Fatalf("Expected to be left of the first leg, received %v", deviations[1])
+	}
+	if deviations[2].Leg != 1 || deviations[2].CrossTrack <= 0 {
+		t.Fatalf("Expected to be right of the second leg, received %v", deviations[2])
+	}
+	expected := RadiansToNM(CrossTrackError(route[1].Coord, route[2].Coord, deviations[2].Point.Coord))
+	if deviations[2].CrossTrack != expected {
+		t.Fatalf("Expected: %v, received %v", expected, deviations[2].CrossTrack)
+	}
+}
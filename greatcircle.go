@@ -3,16 +3,16 @@ package greatcircle
 /*
 Library for various Earth coordinate & Great Circle calcuations.
 
-North latitudes and West longitudes are treated as positive, and South latitudes and East longitudes negative
+North latitudes and West longitudes are treated as positive, and South latitudes and East longitudes negative.
+FromLatLonDegrees and LatLonDegrees convert from and to the North and East positive convention used elsewhere.
 */
 
 import (
 	"errors"
-	"fmt"
 	"math"
 	"sort"
 	"strconv"
-	"strings"
+	"time"
 )
 
 /*
@@ -22,23 +22,38 @@ type DegreeUnits struct {
 	degree, minute, second float64
 }
 
-// Coordinate is the position on earth in latitude/longitude.
-// Negative longitude represents a longitude line in the western hemisphere.
+// Coordinate is the position on earth in latitude/longitude, in radians.
+// Positive longitude represents a longitude line in the western hemisphere;
+// see FromLatLonDegrees for the East positive convention.
 type Coordinate struct {
 	Latitude  float64
 	Longitude float64
 }
 
+/*
+NewCoordinate parses a latitude and a longitude in degrees, using any of the
+formats accepted by DegreeStrToDecimalDegree.
+
+A complete ISO 6709 string, such as one written by ToISO6709, may be given as
+the latitude with an empty longitude. An ISO 6709 longitude on its own cannot
+be told apart from a signed longitude in this library's convention, so is read
+West positive.
+
+The result is normalised; a *CoordinateError is returned for a latitude beyond a pole.
+*/
 func NewCoordinate(latitude string, longitude string) (Coordinate, error) {
-	latitudeDegrees, err := DegreeStrToDecimalDegree(latitude)
+	if longitude == "" {
+		return ParseISO6709(latitude)
+	}
+	latitudeDegrees, err := parseAngle(latitude, 0, len(latitude), latitudeAxis)
 	if err != nil {
 		return Coordinate{}, err
 	}
-	longitudeDegrees, err := DegreeStrToDecimalDegree(longitude)
+	longitudeDegrees, err := parseAngle(longitude, 0, len(longitude), longitudeAxis)
 	if err != nil {
 		return Coordinate{}, err
 	}
-	return Coordinate{DegreesToRadians(latitudeDegrees), DegreesToRadians(longitudeDegrees)}, nil
+	return NewCoordinateDegrees(latitudeDegrees, longitudeDegrees)
 }
 
 /*
@@ -59,9 +74,10 @@ Response format: latitude:longitude
 SkyVector uses -ve for west & +ve for east. This library is the opposite.
 */
 func (coord Coordinate) ToSkyVector() (out string) {
-	out = strconv.FormatFloat(RadiansToDegrees(coord.Latitude), 'f', 2, 64)
+	latitude, longitude := coord.LatLonDegrees()
+	out = strconv.FormatFloat(latitude, 'f', 2, 64)
 	out = out + ":"
-	out = out + strconv.FormatFloat(-1*RadiansToDegrees(coord.Longitude), 'f', 2, 64)
+	out = out + strconv.FormatFloat(longitude, 'f', 2, 64)
 	return
 }
 
@@ -80,6 +96,55 @@ type NamedCoordinate struct {
 	Name  string
 }
 
+/*
+LegType describes the path travelled between two consecutive
+coordinates of a LegTypedRoute.
+*/
+type LegType int
+
+const (
+	// GreatCircleLeg follows the shortest path between the coordinates
+	GreatCircleLeg LegType = iota
+	// RhumbLineLeg follows a constant true course between the coordinates
+	RhumbLineLeg
+)
+
+var legTypeNames = []string{"great-circle", "rhumb-line"}
+
+func (legType LegType) String() string {
+	if legType < 0 || int(legType) >= len(legTypeNames) {
+		return "LegType(" + strconv.Itoa(int(legType)) + ")"
+	}
+	return legTypeNames[legType]
+}
+
+/*
+MarshalText encodes the LegType as "great-circle" or "rhumb-line".
+*/
+func (legType LegType) MarshalText() ([]byte, error) {
+	if legType < 0 || int(legType) >= len(legTypeNames) {
+		return nil, errors.New("greatcircle: unknown " + legType.String())
+	}
+	return []byte(legType.String()), nil
+}
+
+/*
+UnmarshalText decodes "great-circle" or "rhumb-line"; an empty string is a great circle.
+*/
+func (legType *LegType) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*legType = GreatCircleLeg
+		return nil
+	}
+	for i, name := range legTypeNames {
+		if string(text) == name {
+			*legType = LegType(i)
+			return nil
+		}
+	}
+	return errors.New("greatcircle: unknown leg type " + strconv.Quote(string(text)))
+}
+
 func NewNamedCoordinate(name string, latitude string, longitude string) (NamedCoordinate, error) {
 	coord, err := NewCoordinate(latitude, longitude)
 	if err != nil {
@@ -108,22 +173,166 @@ type Radial struct {
 	Bearing float64
 }
 
-func DegreeStrToDecimalDegree(degrees string) (float64, error) {
-	units := strings.Split(degrees, ":")
-	if len(units) > 3 {
-		return 0, errors.New(degrees + " should have 3 or fewer portions")
-	}
-	unitMultiplier := 1.0
-	decimalDegree := 0.0
-	for _, unit := range units {
-		unitValue, err := strconv.ParseFloat(unit, 64)
-		if err != nil {
-			return 0, err
+/*
+Destination determines the Coordinate reached by travelling distance
+nautical miles from coord along the great circle that commences on the
+initial true course bearing (radians).
+
+Starting at a pole, every course leads along the meridian of the
+starting Longitude.
+
+Calculated using the formula from http://williams.best.vwh.net/avform.htm#LL
+*/
+func (coord Coordinate) Destination(bearing, distance float64) Coordinate {
+	d := NMToRadians(distance)
+	latitude := math.Asin(math.Sin(coord.Latitude)*math.Cos(d) +
+		math.Cos(coord.Latitude)*math.Sin(d)*math.Cos(bearing))
+	if math.Abs(math.Cos(coord.Latitude)) < 1e-15 {
+		longitude := coord.Longitude
+		if math.Sin(d) < 0 {
+			// travelled across the opposite pole
+			longitude += math.Pi
 		}
-		decimalDegree = decimalDegree + unitValue/unitMultiplier
-		unitMultiplier = unitMultiplier * 60
+		return Coordinate{latitude, math.Remainder(longitude, 2*math.Pi)}
+	}
+	dlon := math.Atan2(math.Sin(bearing)*math.Sin(d)*math.Cos(coord.Latitude),
+		math.Cos(d)-math.Sin(coord.Latitude)*math.Sin(latitude))
+	return Coordinate{latitude, math.Remainder(coord.Longitude-dlon, 2*math.Pi)}
+}
+
+/*
+Destination determines the Coordinate reached by travelling distance
+nautical miles along the Radial.
+*/
+func (radial Radial) Destination(distance float64) Coordinate {
+	return radial.Coordinate.Destination(radial.Bearing, distance)
+}
+
+/*
+BearingAtDistance provides the true course at the point distance nautical
+miles along the Radial; see BearingAtDistance.
+*/
+func (radial Radial) BearingAtDistance(distance float64) float64 {
+	if distance == 0 {
+		return radial.Bearing
+	}
+	return FinalBearing(radial.Coordinate, radial.Destination(distance))
+}
+
+/*
+IntermediatePoint determines the Coordinate that is fraction of the way along
+the great circle from point1 to point2; 0 is point1, 0.5 the midpoint and 1 point2.
+Fractions outside [0, 1] continue along the great circle beyond the two points.
+
+Antipodal points are joined by every great circle through them; the one
+followed is that of InitialBearing from point1.
+
+Calculated using the formula from http://williams.best.vwh.net/avform.htm#Intermediate
+*/
+func IntermediatePoint(point1, point2 Coordinate, fraction float64) Coordinate {
+	d := NMToRadians(Distance(point1, point2))
+	if math.Sin(d) < 1e-12 {
+		return point1.Destination(InitialBearing(point1, point2), fraction*RadiansToNM(d))
 	}
-	return decimalDegree, nil
+	a := math.Sin((1-fraction)*d) / math.Sin(d)
+	b := math.Sin(fraction*d) / math.Sin(d)
+	x := a*math.Cos(point1.Latitude)*math.Cos(point1.Longitude) + b*math.Cos(point2.Latitude)*math.Cos(point2.Longitude)
+	y := a*math.Cos(point1.Latitude)*math.Sin(point1.Longitude) + b*math.Cos(point2.Latitude)*math.Sin(point2.Longitude)
+	z := a*math.Sin(point1.Latitude) + b*math.Sin(point2.Latitude)
+	return Coordinate{math.Atan2(z, math.Sqrt(x*x+y*y)), math.Atan2(y, x)}
+}
+
+/*
+IntermediatePoints returns n+1 Coordinates dividing the great circle from
+point1 to point2 into n equal parts, including both point1 and point2.
+*/
+func IntermediatePoints(point1, point2 Coordinate, n int) []Coordinate {
+	return intermediatePoints(point1, point2, n, IntermediatePoint)
+}
+
+/*
+Densify returns Coordinates along the great circle from point1 to point2,
+including both, no more than maxDistance nautical miles apart.
+*/
+func Densify(point1, point2 Coordinate, maxDistance float64) []Coordinate {
+	return IntermediatePoints(point1, point2, densifyParts(Distance(point1, point2), maxDistance))
+}
+
+// intermediatePoints divides a leg into n equal parts using the intermediate point function
+func intermediatePoints(point1, point2 Coordinate, n int, intermediate func(Coordinate, Coordinate, float64) Coordinate) []Coordinate {
+	if n < 1 {
+		n = 1
+	}
+	points := []Coordinate{point1}
+	for i := 1; i < n; i++ {
+		points = append(points, intermediate(point1, point2, float64(i)/float64(n)))
+	}
+	return append(points, point2)
+}
+
+// densifyParts is how many equal parts a leg of distance nautical miles is divided into
+func densifyParts(distance, maxDistance float64) int {
+	if maxDistance <= 0 {
+		return 1
+	}
+	return int(math.Ceil(distance / maxDistance))
+}
+
+/*
+splitAntimeridian splits the line through coords into parts that do not cross
+the antimeridian, for formats drawn on a flat map, where a line from 179E to
+179W would otherwise cross the whole map. A part that crosses ends on the
+antimeridian and the next begins there, on the other side.
+*/
+func splitAntimeridian(coords []Coordinate) [][]Coordinate {
+	if len(coords) == 0 {
+		return nil
+	}
+	parts := [][]Coordinate{{coords[0]}}
+	for i := 1; i < len(coords); i++ {
+		from, to := coords[i-1], coords[i]
+		if math.Abs(to.Longitude-from.Longitude) > math.Pi {
+			latitude := antimeridianLatitude(from, to)
+			// West is positive, so -pi is written as 180 East and pi as 180 West
+			edge := math.Copysign(math.Pi, from.Longitude)
+			last := len(parts) - 1
+			parts[last] = append(parts[last], Coordinate{latitude, edge})
+			parts = append(parts, []Coordinate{{latitude, -edge}})
+		}
+		parts[len(parts)-1] = append(parts[len(parts)-1], to)
+	}
+	return parts
+}
+
+// antimeridianLatitude is the latitude at which the great circle from one Coordinate to another crosses the antimeridian
+func antimeridianLatitude(from, to Coordinate) float64 {
+	return math.Atan((math.Sin(from.Latitude)*math.Cos(to.Latitude)*math.Sin(math.Pi-to.Longitude) -
+		math.Sin(to.Latitude)*math.Cos(from.Latitude)*math.Sin(math.Pi-from.Longitude)) /
+		(math.Cos(from.Latitude) * math.Cos(to.Latitude) * math.Sin(from.Longitude-to.Longitude)))
+}
+
+/*
+DegreeStrToDecimalDegree parses a latitude or longitude into decimal degrees.
+
+Degrees, minutes and seconds may be separated by colons, spaces, hyphens or
+the usual symbols, with an optional leading sign or a N/S/E/W hemisphere
+letter before or after the value:
+
+	37:37:00
+	37°37'00"N
+	N37 37.0
+	122-22-00W
+	37.6167N
+	3737N
+	1222200W
+
+Following this library's convention North and West are positive, South and
+East negative. Problems are reported as a *ParseError, which wraps
+ErrInvalidLatitude or ErrInvalidLongitude for more than 90 degrees of latitude
+or 180 of longitude.
+*/
+func DegreeStrToDecimalDegree(degrees string) (float64, error) {
+	return parseAngle(degrees, 0, len(degrees), anyAxis)
 }
 
 /*
@@ -170,7 +379,7 @@ The shortest distance between two coordinates is the arc across the
 great circle that includes the two points.
 */
 func Distance(point1, point2 Coordinate) float64 {
-	return (math.Acos(math.Sin(point1.Latitude)*math.Sin(point2.Latitude)+
+	return (acos(math.Sin(point1.Latitude)*math.Sin(point2.Latitude)+
 		math.Cos(point1.Latitude)*math.Cos(point2.Latitude)*math.Cos(point1.Longitude-point2.Longitude)) * 180 * 60) / math.Pi
 }
 
@@ -187,22 +396,57 @@ will change. This function returns the bearing at point1.
 func InitialBearing(point1, point2 Coordinate) float64 {
 	var tc float64
 	var argacos float64
-	d := math.Acos(math.Sin(point1.Latitude)*math.Sin(point2.Latitude) + math.Cos(point1.Latitude)*math.Cos(point2.Latitude)*math.Cos(point1.Longitude-point2.Longitude))
-	if (d == 0.) || (point1.Latitude == -(math.Pi/180)*90.) {
+	d := acos(math.Sin(point1.Latitude)*math.Sin(point2.Latitude) + math.Cos(point1.Latitude)*math.Cos(point2.Latitude)*math.Cos(point1.Longitude-point2.Longitude))
+	if (d == 0.) || (point1.Latitude <= -(math.Pi/180)*90.) {
 		tc = 2 * math.Pi
-	} else if point1.Latitude == (math.Pi/180)*90. {
+	} else if point1.Latitude >= (math.Pi/180)*90. {
 		tc = math.Pi
 	} else {
 		argacos = (math.Sin(point2.Latitude) - math.Sin(point1.Latitude)*math.Cos(d)) / (math.Sin(d) * math.Cos(point1.Latitude))
 		if math.Sin(point2.Longitude-point1.Longitude) < 0 {
-			tc = math.Acos(argacos)
+			tc = acos(argacos)
 		} else {
-			tc = 2*math.Pi - math.Acos(argacos)
+			tc = 2*math.Pi - acos(argacos)
 		}
 	}
 	return tc
 }
 
+/*
+FinalBearing provides the true course on arrival at point2 at the end of a
+journey along a great circle from point1.
+
+Result is in radians, in (0, 2pi] so that, as with InitialBearing, due
+North is 2pi. For coincident points the result is 2pi, and on arrival at a
+pole it is the course along the meridian: 2pi at the North pole, pi at the
+South pole.
+*/
+func FinalBearing(point1, point2 Coordinate) float64 {
+	if Distance(point1, point2) == 0 {
+		return 2 * math.Pi
+	}
+	tc := math.Mod(InitialBearing(point2, point1)+math.Pi, 2*math.Pi)
+	if tc <= 0 {
+		tc += 2 * math.Pi
+	}
+	return tc
+}
+
+/*
+BearingAtDistance provides the true course at the point distance nautical
+miles along the great circle from point1 towards point2.
+
+Result is in radians, as for FinalBearing. A distance of zero is the
+InitialBearing; distances must be less than half way around the Earth
+(10800 nautical miles) but may go beyond point2.
+*/
+func BearingAtDistance(point1, point2 Coordinate, distance float64) float64 {
+	if distance == 0 {
+		return InitialBearing(point1, point2)
+	}
+	return FinalBearing(point1, point1.Destination(InitialBearing(point1, point2), distance))
+}
+
 /*
 IntersectionRadials determines the Coordinate that two Radials
 would interset.
@@ -210,20 +454,28 @@ would interset.
 Adapted from http://webcache.googleusercontent.com/search?q=cache:qhjJEsGLvSUJ:williams.best.vwh.net/avform.htm+&cd=1&hl=en&ct=clnk&gl=au#Intersection
 */
 func IntersectionRadials(radial1, radial2 Radial) (coordinate Coordinate, err error) {
-	dst12 := 2 * math.Asin(math.Sqrt(math.Pow((math.Sin((radial1.Latitude-radial2.Latitude)/2)), 2)+
+	dst12 := 2 * asin(math.Sqrt(math.Pow((math.Sin((radial1.Latitude-radial2.Latitude)/2)), 2)+
 		math.Cos(radial1.Latitude)*math.Cos(radial2.Latitude)*math.Pow(math.Sin((radial1.Longitude-radial2.Longitude)/2), 2)))
-	// initial/final bearings between points
-	crs13 := InitialBearing(radial1.Coordinate, radial2.Coordinate)
-	crs23 := InitialBearing(radial2.Coordinate, radial1.Coordinate)
+	// bearings of the radials
+	crs13 := radial1.Bearing
+	crs23 := radial2.Bearing
+
+	if dst12 == 0 {
+		// both radials start from the same point
+		if math.Sin(crs13-crs23) == 0 {
+			return Coordinate{0, 0}, errors.New("infinity of intersections")
+		}
+		return radial1.Coordinate, nil
+	}
 
 	var crs12 float64
 	var crs21 float64
 	if math.Sin(radial2.Longitude-radial1.Longitude) < 0 {
-		crs12 = math.Acos((math.Sin(radial2.Latitude) - math.Sin(radial1.Latitude)*math.Cos(dst12)) / (math.Sin(dst12) * math.Cos(radial1.Latitude)))
-		crs21 = 2.*math.Pi - math.Acos((math.Sin(radial1.Latitude)-math.Sin(radial2.Latitude)*math.Cos(dst12))/(math.Sin(dst12)*math.Cos(radial2.Latitude)))
+		crs12 = acos((math.Sin(radial2.Latitude) - math.Sin(radial1.Latitude)*math.Cos(dst12)) / (math.Sin(dst12) * math.Cos(radial1.Latitude)))
+		crs21 = 2.*math.Pi - acos((math.Sin(radial1.Latitude)-math.Sin(radial2.Latitude)*math.Cos(dst12))/(math.Sin(dst12)*math.Cos(radial2.Latitude)))
 	} else {
-		crs12 = 2.*math.Pi - math.Acos((math.Sin(radial2.Latitude)-math.Sin(radial1.Latitude)*math.Cos(dst12))/(math.Sin(dst12)*math.Cos(radial1.Latitude)))
-		crs21 = math.Acos((math.Sin(radial1.Latitude) - math.Sin(radial2.Latitude)*math.Cos(dst12)) / (math.Sin(dst12) * math.Cos(radial2.Latitude)))
+		crs12 = 2.*math.Pi - acos((math.Sin(radial2.Latitude)-math.Sin(radial1.Latitude)*math.Cos(dst12))/(math.Sin(dst12)*math.Cos(radial1.Latitude)))
+		crs21 = acos((math.Sin(radial1.Latitude) - math.Sin(radial2.Latitude)*math.Cos(dst12)) / (math.Sin(dst12) * math.Cos(radial2.Latitude)))
 	}
 
 	ang1 := math.Mod(crs13-crs12+math.Pi, 2.*math.Pi) - math.Pi
@@ -236,11 +488,11 @@ func IntersectionRadials(radial1, radial2 Radial) (coordinate Coordinate, err er
 	} else {
 		ang1 := math.Abs(ang1)
 		ang2 := math.Abs(ang2)
-		ang3 := math.Acos(-math.Cos(ang1)*math.Cos(ang2) + math.Sin(ang1)*math.Sin(ang2)*math.Cos(dst12))
+		ang3 := acos(-math.Cos(ang1)*math.Cos(ang2) + math.Sin(ang1)*math.Sin(ang2)*math.Cos(dst12))
 		dst13 := math.Atan2(math.Sin(dst12)*math.Sin(ang1)*math.Sin(ang2), math.Cos(ang2)+math.Cos(ang1)*math.Cos(ang3))
-		lat3 := math.Asin(math.Sin(radial1.Latitude)*math.Cos(dst13) + math.Cos(radial1.Latitude)*math.Sin(dst13)*math.Cos(crs13))
+		lat3 := asin(math.Sin(radial1.Latitude)*math.Cos(dst13) + math.Cos(radial1.Latitude)*math.Sin(dst13)*math.Cos(crs13))
 		dlon := math.Atan2(math.Sin(crs13)*math.Sin(dst13)*math.Cos(radial1.Latitude), math.Cos(dst13)-math.Sin(radial1.Latitude)*math.Sin(lat3))
-		lon3 := math.Mod(radial1.Longitude-dlon+math.Pi, 2*math.Pi) - math.Pi
+		lon3 := wrapLongitude(radial1.Longitude - dlon)
 		return Coordinate{lat3, lon3}, nil
 	}
 }
@@ -259,7 +511,7 @@ func CrossTrackError(routeStartCoord, routeEndCoord, actualCoord Coordinate) flo
 	crsAD := InitialBearing(routeStartCoord, actualCoord)
 	initialBearing := InitialBearing(routeStartCoord, routeEndCoord)
 	// crosstrack error
-	xtd := math.Asin(math.Sin(distAD) * math.Sin(crsAD-initialBearing))
+	xtd := asin(math.Sin(distAD) * math.Sin(crsAD-initialBearing))
 	return xtd
 }
 
@@ -274,84 +526,76 @@ func AlongTrackDistance(routeStartCoord, routeEndCoord, actualCoord Coordinate)
 	distAD := NMToRadians(Distance(routeStartCoord, actualCoord))
 	// along track distance
 	xtd := CrossTrackError(routeStartCoord, routeEndCoord, actualCoord)
-	atd := math.Asin(math.Sqrt(math.Pow((math.Sin(distAD)), 2)-math.Pow((math.Sin(xtd)), 2)) / math.Cos(xtd))
+	atd := asin(math.Sqrt(math.Max(0, math.Pow((math.Sin(distAD)), 2)-math.Pow((math.Sin(xtd)), 2))) / math.Cos(xtd))
 	return atd
 }
 
+/*
+alongTrack is the signed along track distance in radians from routeStartCoord to
+the point abeam actualCoord; negative when that point is behind routeStartCoord.
+*/
+func alongTrack(routeStartCoord, routeEndCoord, actualCoord Coordinate) float64 {
+	distAD := NMToRadians(Distance(routeStartCoord, actualCoord))
+	angle := InitialBearing(routeStartCoord, actualCoord) - InitialBearing(routeStartCoord, routeEndCoord)
+	return math.Atan2(math.Sin(distAD)*math.Cos(angle), math.Cos(distAD))
+}
+
+/*
+segmentClosestPoint is the point between routeStartCoord and routeEndCoord that is
+closest to actualCoord; unlike ClosestPoint it is never beyond either end.
+*/
+func segmentClosestPoint(routeStartCoord, routeEndCoord, actualCoord Coordinate) Coordinate {
+	along := alongTrack(routeStartCoord, routeEndCoord, actualCoord)
+	if along <= 0 {
+		return routeStartCoord
+	}
+	distance := RadiansToNM(along)
+	if distance >= Distance(routeStartCoord, routeEndCoord) {
+		return routeEndCoord
+	}
+	return routeStartCoord.Destination(InitialBearing(routeStartCoord, routeEndCoord), distance)
+}
+
 /*
 ClosestPoint determines the coordinate for the closest point along a course/radial from the actualCoord.
 
 Calculated using the formula from http://webcache.googleusercontent.com/search?q=cache:qhjJEsGLvSUJ:williams.best.vwh.net/avform.htm+&cd=1&hl=en&ct=clnk&gl=au#Example - enroute waypoint.
 */
 func ClosestPoint(routeStartCoord, routeEndCoord, actualCoord Coordinate) Coordinate {
-	var coordinate Coordinate
 	bearing := InitialBearing(routeStartCoord, routeEndCoord)
 	distance := AlongTrackDistance(routeStartCoord, routeEndCoord, actualCoord)
-	coordinate.Latitude = math.Asin(math.Sin(routeStartCoord.Latitude)*math.Cos(distance) +
-		math.Cos(routeStartCoord.Latitude)*math.Sin(distance)*math.Cos(bearing))
-	coordinate.Longitude = math.Mod(routeStartCoord.Longitude-math.Asin(math.Sin(bearing)*math.Sin(distance)/math.Cos(coordinate.Latitude))+math.Pi, 2*math.Pi) - math.Pi
-	return coordinate
+	return routeStartCoord.Destination(bearing, RadiansToNM(distance))
 }
 
 /*
-pointOfReachDistance is a helper function for PointInReach and PointsInReach
-first we use the ClosestPoint function to get the first point (the closest to the provided point3)
-and then compute the distance to the given point3 and compare it against the given distance
-*/
-func pointOfReachDistance(point1, point2, point3 Coordinate) float64 {
-	closestpoint := ClosestPoint(point1, point2, point3)
-	distanceBetweenPoints := Distance(closestpoint, point3)
-
-	return distanceBetweenPoints
-}
-
-/*
-PointInReach determines if actualCoord is within testDistance of the route from
+PointInReach determines if point3 is within distance nautical miles of the route
+from point1 to point2, as PointsInReach does. A point beyond either end of the
+route is measured from that end, not from the great circle continued past it.
 */
 func PointInReach(point1, point2, point3 Coordinate, distance float64) (response bool) {
-	/*
-		using the helper function above we find the point3 in reach and get the distance
-		of to point3. Comparing the expected distance with the provided distance
-		the function returns true if point3 is in range, else false
-	*/
-	distanceBetweenPoints := pointOfReachDistance(point1, point2, point3)
-	return distanceBetweenPoints <= distance
+	_, response = reachResult(point1, point2, distance, point3)
+	return
 }
 
 /*
 PointsInReach filters a list of Coordinates to return only those Coordinates that are within testDistance
-of the (routeStartCoord, routeEndCoord) route.
+of the (routeStartCoord, routeEndCoord) route, nearest first.
+
+Every match is returned, including Coordinates the same distance from the route;
+see FindPointsInReach for where each lies relative to the route. Coordinates
+beyond either end of the route are measured from that end.
 */
 func PointsInReach(routeStartCoord, routeEndCoord Coordinate, distance float64, coords []Coordinate) []Coordinate {
-	/*
-		providing an array of points, the helper function is used to get the distance
-		 to those points and then compared with the distance provided.
-		 If the points are within distance, they are returned sorted
-	*/
-	pointsInReach := make(map[float64]Coordinate)
-	var sortedPointsInReach []Coordinate
-
-	for _, coord := range coords {
-		distanceBetweenPoints := pointOfReachDistance(routeStartCoord, routeEndCoord, coord)
-		if distanceBetweenPoints <= distance {
-			pointsInReach[distanceBetweenPoints] = coord
-		}
-
-	}
-	keys := make([]float64, 0, len(pointsInReach))
-	for k := range pointsInReach {
-		keys = append(keys, k)
-	}
-	sort.Float64s(keys)
-
-	for _, k := range keys {
-		sortedPointsInReach = append(sortedPointsInReach, pointsInReach[k])
-	}
-
-	return sortedPointsInReach
-
+	results := FindPointsInReach(routeStartCoord, routeEndCoord, distance, coords)
+	results.SortByDistance()
+	return results.Coordinates()
 }
 
+/*
+MultiPointRoute is a route through NamedCoordinates, in order, with every leg a
+great circle. WithLegTypes makes a LegTypedRoute of it, whose legs may be rhumb
+lines.
+*/
 type MultiPointRoute []NamedCoordinate
 
 func NewMultiPointRoute(coords []NamedCoordinate) (route MultiPointRoute) {
@@ -376,10 +620,219 @@ func (route MultiPointRoute) ToSkyVector() (out string) {
 	return
 }
 
+/*
+WithLegTypes returns a LegTypedRoute through the points of the route, with its
+legs travelled as legTypes, in order. Legs past the end of legTypes are great
+circles.
+*/
+func (route MultiPointRoute) WithLegTypes(legTypes ...LegType) LegTypedRoute {
+	return LegTypedRoute{Points: route, legs: append([]LegType(nil), legTypes...)}
+}
+
+/*
+Distance calculates the total length of the route in nautical miles, following
+each leg as a great circle.
+*/
+func (route MultiPointRoute) Distance() float64 {
+	return route.WithLegTypes().Distance()
+}
+
+/*
+POIS filters a list of points of interest to those within distance nautical
+miles of the route, as LegTypedRoute's POIS does with every leg a great circle.
+*/
+func (route MultiPointRoute) POIS(pois []Coordinate, distance float64, opts ...POIOption) []MultiPoint {
+	return route.WithLegTypes().POIS(pois, distance, opts...)
+}
+
+/*
+LegIntermediatePoint determines the Coordinate that is fraction of the way along
+the great circle leg from route[leg] to route[leg+1].
+*/
+func (route MultiPointRoute) LegIntermediatePoint(leg int, fraction float64) Coordinate {
+	return route.WithLegTypes().LegIntermediatePoint(leg, fraction)
+}
+
+/*
+LegIntermediatePoints returns n+1 Coordinates dividing the great circle leg from
+route[leg] to route[leg+1] into n equal parts, including both ends.
+*/
+func (route MultiPointRoute) LegIntermediatePoints(leg int, n int) []Coordinate {
+	return route.WithLegTypes().LegIntermediatePoints(leg, n)
+}
+
+/*
+DensifyLeg returns Coordinates along the great circle leg from route[leg] to
+route[leg+1], including both ends, no more than maxDistance nautical miles apart.
+*/
+func (route MultiPointRoute) DensifyLeg(leg int, maxDistance float64) []Coordinate {
+	return route.WithLegTypes().DensifyLeg(leg, maxDistance)
+}
+
+/*
+PointAtDistance determines the Coordinate distance nautical miles along the
+route from its start, and the index of the leg it is on, as LegTypedRoute's
+PointAtDistance does with every leg a great circle.
+*/
+func (route MultiPointRoute) PointAtDistance(distance float64) (Coordinate, int) {
+	return route.WithLegTypes().PointAtDistance(distance)
+}
+
+/*
+LegTypedRoute is a MultiPointRoute with how each of its legs is travelled. Each
+leg between consecutive Points is a great circle unless SetLegType says
+otherwise, and every route-level calculation follows it as its LegType says.
+*/
+type LegTypedRoute struct {
+	Points MultiPointRoute
+	// legs[i] is the LegType of the leg from Points[i] to Points[i+1]; legs past its end are great circles
+	legs []LegType
+}
+
+/*
+SetLegType sets how the leg from Points[leg] to Points[leg+1] is travelled.
+*/
+func (route *LegTypedRoute) SetLegType(leg int, legType LegType) {
+	for len(route.legs) <= leg {
+		route.legs = append(route.legs, GreatCircleLeg)
+	}
+	route.legs[leg] = legType
+}
+
+/*
+LegType returns how the leg from Points[leg] to Points[leg+1] is travelled.
+*/
+func (route LegTypedRoute) LegType(leg int) LegType {
+	if leg < 0 || leg >= len(route.legs) {
+		return GreatCircleLeg
+	}
+	return route.legs[leg]
+}
+
+/*
+Distance calculates the total length of the route, following each leg as
+a great circle or rhumb line according to its LegType.
+
+Result is in nautical miles.
+*/
+func (route LegTypedRoute) Distance() (total float64) {
+	for leg := 0; leg+1 < len(route.Points); leg++ {
+		total = total + route.legDistance(leg)
+	}
+	return
+}
+
+/*
+POIS filters a list of points of interest to those within distance nautical miles
+of the route, as MultiPointRoutePOIS does, but following each leg as a great circle
+or rhumb line according to its LegType. Points of interest beyond either end of a
+leg are measured from that end.
+*/
+func (route LegTypedRoute) POIS(pois []Coordinate, distance float64, opts ...POIOption) []MultiPoint {
+	options := newPOIOptions(opts)
+	finalPoisInReach := []MultiPoint{}
+	seen := map[Coordinate]bool{}
+	for leg := 0; leg+1 < len(route.Points); leg++ {
+		start := time.Now()
+		var legPoisInReach []MultiPoint
+		for _, poi := range pois {
+			nearest := route.legClosestPoint(leg, poi)
+			distanceBetweenPoints := Distance(nearest, poi)
+			if distanceBetweenPoints <= distance {
+				legPoisInReach = append(legPoisInReach, MultiPoint{poi, nearest, distanceBetweenPoints})
+			}
+		}
+		sort.SliceStable(legPoisInReach, func(i, j int) bool {
+			return legPoisInReach[i].Distance < legPoisInReach[j].Distance
+		})
+		options.trace(LegTrace{leg, route.Points[leg].Coord, route.Points[leg+1].Coord, len(pois), len(legPoisInReach), time.Since(start)})
+		for _, mps := range legPoisInReach {
+			if !seen[mps.Poi] {
+				finalPoisInReach = append(finalPoisInReach, mps)
+				seen[mps.Poi] = true
+			}
+		}
+	}
+	return finalPoisInReach
+}
+
+/*
+LegIntermediatePoint determines the Coordinate that is fraction of the way along
+the leg from Points[leg] to Points[leg+1], following its LegType.
+*/
+func (route LegTypedRoute) LegIntermediatePoint(leg int, fraction float64) Coordinate {
+	if route.LegType(leg) == RhumbLineLeg {
+		return RhumbIntermediatePoint(route.Points[leg].Coord, route.Points[leg+1].Coord, fraction)
+	}
+	return IntermediatePoint(route.Points[leg].Coord, route.Points[leg+1].Coord, fraction)
+}
+
+/*
+LegIntermediatePoints returns n+1 Coordinates dividing the leg from Points[leg]
+to Points[leg+1] into n equal parts, including both ends, following its LegType.
+*/
+func (route LegTypedRoute) LegIntermediatePoints(leg int, n int) []Coordinate {
+	if route.LegType(leg) == RhumbLineLeg {
+		return RhumbIntermediatePoints(route.Points[leg].Coord, route.Points[leg+1].Coord, n)
+	}
+	return IntermediatePoints(route.Points[leg].Coord, route.Points[leg+1].Coord, n)
+}
+
+/*
+DensifyLeg returns Coordinates along the leg from Points[leg] to Points[leg+1],
+including both ends, no more than maxDistance nautical miles apart, following
+its LegType.
+*/
+func (route LegTypedRoute) DensifyLeg(leg int, maxDistance float64) []Coordinate {
+	return route.LegIntermediatePoints(leg, densifyParts(route.legDistance(leg), maxDistance))
+}
+
+/*
+PointAtDistance determines the Coordinate distance nautical miles along the
+route from its start, following each leg according to its LegType, and the
+index of the leg it is on. Distances beyond either end of the route are
+limited to the route itself. A route of a single point is that point, on leg
+0; an empty route has no point, and the leg is -1.
+*/
+func (route LegTypedRoute) PointAtDistance(distance float64) (Coordinate, int) {
+	if len(route.Points) == 0 {
+		return Coordinate{}, -1
+	}
+	if len(route.Points) < 2 {
+		return route.Points[0].Coord, 0
+	}
+	leg := 0
+	for ; leg+2 < len(route.Points) && distance > route.legDistance(leg); leg++ {
+		distance = distance - route.legDistance(leg)
+	}
+	fraction := 0.0
+	if legDistance := route.legDistance(leg); legDistance > 0 {
+		fraction = math.Max(0, math.Min(1, distance/legDistance))
+	}
+	return route.LegIntermediatePoint(leg, fraction), leg
+}
+
+// legDistance is the length of the leg from Points[leg] to Points[leg+1] in nautical miles
+func (route LegTypedRoute) legDistance(leg int) float64 {
+	if route.LegType(leg) == RhumbLineLeg {
+		return RhumbDistance(route.Points[leg].Coord, route.Points[leg+1].Coord)
+	}
+	return Distance(route.Points[leg].Coord, route.Points[leg+1].Coord)
+}
+
+// legClosestPoint is the point on the leg from Points[leg] to Points[leg+1] closest to coord, never beyond either end
+func (route LegTypedRoute) legClosestPoint(leg int, coord Coordinate) Coordinate {
+	if route.LegType(leg) == RhumbLineLeg {
+		return RhumbClosestPoint(route.Points[leg].Coord, route.Points[leg+1].Coord, coord)
+	}
+	return segmentClosestPoint(route.Points[leg].Coord, route.Points[leg+1].Coord, coord)
+}
+
 /* MultiPointRoutePOIS takes a 2 lists of coordinates and a distance. The first list of coordinates
 will be used to form the multi point route and the second list will be the point of interest list which will be within
 the provided distance.
 It returns a struct for each match with the point of interest coordinates, the neareast point on the route to the poi and the distance between the nearest poit and the poi.
+A point of interest beyond either end of a leg is measured from that end, so one near only the great circle continued past the leg is not matched.
 */
 
 type MultiPoint struct {
@@ -388,21 +841,28 @@ type MultiPoint struct {
 	Distance float64
 }
 
-func MultiPointRoutePOIS(routePoints, pois []Coordinate, distance float64) []MultiPoint {
+func MultiPointRoutePOIS(routePoints, pois []Coordinate, distance float64, opts ...POIOption) []MultiPoint {
+	return multiPointRoutePOIS(routePoints, distance, newPOIOptions(opts), legPointsInReach(pois, distance))
+}
+
+// legPointsInReach finds the points in reach of a leg, nearest first, testing every one of pois
+func legPointsInReach(pois []Coordinate, distance float64) func(Coordinate, Coordinate) (ReachResults, int) {
+	return func(routeStartCoord, routeEndCoord Coordinate) (ReachResults, int) {
+		results := FindPointsInReach(routeStartCoord, routeEndCoord, distance, pois)
+		results.SortByDistance()
+		return results, len(pois)
+	}
+}
+
+/*
+multiPointRoutePOIS is MultiPointRoutePOIS with the points in reach of each leg, and
+the number of candidates tested, found by pointsInReach
+*/
+func multiPointRoutePOIS(routePoints []Coordinate, distance float64, options poiOptions, pointsInReach func(Coordinate, Coordinate) (ReachResults, int)) []MultiPoint {
 	var multiPoint []MultiPoint
 	for i, point := range routePoints {
 		if len(routePoints) > i+1 {
-			poistemp := PointsInReach(point, routePoints[i+1], distance, pois)
-			fmt.Println(point, routePoints[i+1], distance, pois)
-			for _, poi := range poistemp {
-				mps := MultiPoint{}
-				mps.Poi = poi
-				mps.Neareast = ClosestPoint(point, routePoints[i+1], poi)
-				mps.Distance = Distance(mps.Neareast, poi)
-
-				// append to the struct
-				multiPoint = append(multiPoint, mps)
-			}
+			multiPoint = append(multiPoint, legMultiPoints(i, point, routePoints[i+1], options, pointsInReach)...)
 		}
 	}
 	// poisInReach can contain duplicate pois, so let's remove the duplicates
@@ -416,3 +876,21 @@ func MultiPointRoutePOIS(routePoints, pois []Coordinate, distance float64) []Mul
 	}
 	return finalPoisInReach
 }
+
+// legMultiPoints finds the points in reach of the leg from routeStartCoord to routeEndCoord
+func legMultiPoints(leg int, routeStartCoord, routeEndCoord Coordinate, options poiOptions, pointsInReach func(Coordinate, Coordinate) (ReachResults, int)) []MultiPoint {
+	start := time.Now()
+	poistemp, candidates := pointsInReach(routeStartCoord, routeEndCoord)
+	var multiPoint []MultiPoint
+	for _, result := range poistemp {
+		mps := MultiPoint{}
+		mps.Poi = result.Coord
+		mps.Neareast = result.Nearest
+		mps.Distance = result.Distance
+
+		// append to the struct
+		multiPoint = append(multiPoint, mps)
+	}
+	options.trace(LegTrace{leg, routeStartCoord, routeEndCoord, candidates, len(poistemp), time.Since(start)})
+	return multiPoint
+}
@@ -100,6 +100,24 @@ var alongtrack = []struct {
 // {coordKLAX.Coord, coordKJFK.Coord, Coordinate{0.6021386, 2.033309}, 0.028969025967186944},
 }
 
+var destination = []struct {
+	point       Coordinate
+	bearing     float64
+	distance    float64
+	destination Coordinate
+}{
+	// 100nm from LAX on the great circle route to JFK, from the Aviation Formulary
+	{coordKLAX.Coord, 1.150035, 100, Coordinate{0.604180, 2.034206}},
+	{Coordinate{0, 0}, 0, 5400, Coordinate{math.Pi / 2, 0}},
+	// from the North pole every course leads down the starting meridian
+	{Coordinate{math.Pi / 2, 1}, DegreesToRadians(45), 5400, Coordinate{0, 1}},
+	{Coordinate{math.Pi / 2, 1}, DegreesToRadians(45), 10800, Coordinate{-math.Pi / 2, 1}},
+	{Coordinate{math.Pi / 2, 1}, DegreesToRadians(45), 16200, Coordinate{0, 1 - math.Pi}},
+	// across the antimeridian
+	{Coordinate{0, DegreesToRadians(179.5)}, DegreesToRadians(270), 60, Coordinate{0, DegreesToRadians(-179.5)}},
+	{Coordinate{0, DegreesToRadians(-179.5)}, DegreesToRadians(90), 60, Coordinate{0, DegreesToRadians(179.5)}},
+}
+
 var pointInReach = []struct {
 	point1      Coordinate
 	point2      Coordinate
@@ -110,6 +128,8 @@ var pointInReach = []struct {
 	{Coordinate{0.6629, 2.1301}, Coordinate{0.6717, 2.1132}, Coordinate{0.6692, 2.1193}, 30, true},
 	{Coordinate{0.6629, 2.1301}, Coordinate{0.6717, 2.1132}, Coordinate{0.6774, 2.1269}, 18, false},
 	{Coordinate{0.9427, 0.4892}, Coordinate{0.9593, 0.8124}, Coordinate{0.9595, 0.6364}, 1, false},
+	// 6 NM from the great circle, but 600 NM beyond the end of the route
+	{Coordinate{0, 0}, Coordinate{0, -0.1745}, Coordinate{0.0017, -0.3491}, 10, false},
 }
 
 var pointsInReach = []struct {
@@ -228,6 +248,160 @@ func TestInitialBearing(t *testing.T) {
 	}
 }
 
+func TestDestination(t *testing.T) {
+	for _, v := range destination {
+		result := v.point.Destination(v.bearing, v.distance)
+		if math.Abs(result.Latitude-v.destination.Latitude) > 0.000001 ||
+			math.Abs(math.Remainder(result.Longitude-v.destination.Longitude, 2*math.Pi)) > 0.000001 {
+			t.Fatalf("Expected: %v, received %v", v.destination, result)
+		}
+	}
+}
+
+func TestRadialDestination(t *testing.T) {
+	radial := Radial{coordKLAX.Coord, InitialBearing(coordKLAX.Coord, coordKJFK.Coord)}
+	result := radial.Destination(Distance(coordKLAX.Coord, coordKJFK.Coord))
+	if !result.Equal(coordKJFK.Coord) {
+		t.Fatalf("Expected: %v, received %v", coordKJFK.Coord, result)
+	}
+}
+
+var intermediates = []struct {
+	point1   Coordinate
+	point2   Coordinate
+	fraction float64
+	expected Coordinate
+}{
+	{coordKLAX.Coord, coordKJFK.Coord, 0, coordKLAX.Coord},
+	{coordKLAX.Coord, coordKJFK.Coord, 1, coordKJFK.Coord},
+	{coordKLAX.Coord, coordKJFK.Coord, 0.37, coordKLAX.Coord.Destination(InitialBearing(coordKLAX.Coord, coordKJFK.Coord), 0.37*Distance(coordKLAX.Coord, coordKJFK.Coord))},
+	// beyond point2
+	{coordKLAX.Coord, coordKJFK.Coord, 1.5, coordKLAX.Coord.Destination(InitialBearing(coordKLAX.Coord, coordKJFK.Coord), 1.5*Distance(coordKLAX.Coord, coordKJFK.Coord))},
+	// along the equator, across the antimeridian
+	{Coordinate{0, DegreesToRadians(175)}, Coordinate{0, DegreesToRadians(-175)}, 0.75, Coordinate{0, DegreesToRadians(-177.5)}},
+	// antipodal points, following InitialBearing
+	{Coordinate{0, 0}, Coordinate{0, math.Pi}, 0.5, Coordinate{0, math.Pi / 2}},
+	{coordKSFO.Coord, coordKSFO.Coord, 0.5, coordKSFO.Coord},
+}
+
+func TestIntermediatePoint(t *testing.T) {
+	for _, v := range intermediates {
+		result := IntermediatePoint(v.point1, v.point2, v.fraction)
+		if Distance(result, v.expected) > 0.0001 {
+			t.Fatalf("Expected: %v, received %v", v.expected, result)
+		}
+	}
+}
+
+func TestIntermediatePoints(t *testing.T) {
+	result := IntermediatePoints(coordKSFO.Coord, coordKJFK.Coord, 4)
+	if len(result) != 5 || result[0] != coordKSFO.Coord || result[4] != coordKJFK.Coord {
+		t.Fatalf("Expected 5 points from KSFO to KJFK, received %v", result)
+	}
+	expected := Distance(coordKSFO.Coord, coordKJFK.Coord) / 4
+	for i := 1; i < len(result); i++ {
+		if math.Abs(Distance(result[i-1], result[i])-expected) > 0.0001 {
+			t.Fatalf("Expected: %v, received %v", expected, Distance(result[i-1], result[i]))
+		}
+	}
+
+	result = Densify(coordKSFO.Coord, coordKLAX.Coord, 100)
+	expected = Distance(coordKSFO.Coord, coordKLAX.Coord)
+	if len(result) != int(math.Ceil(expected/100))+1 {
+		t.Fatalf("Expected %v points, received %v", int(math.Ceil(expected/100))+1, len(result))
+	}
+	if result = Densify(coordKSFO.Coord, coordKLAX.Coord, 1000); len(result) != 2 {
+		t.Fatalf("Expected the end points only, received %v", result)
+	}
+}
+
+func TestSplitAntimeridian(t *testing.T) {
+	tokyo, _ := FromLatLonDegrees(35.55, 139.78)
+	points := Densify(tokyo, coordKSFO.Coord, 200)
+	parts := splitAntimeridian(points)
+	if len(parts) != 2 || len(parts[0])+len(parts[1]) != len(points)+2 {
+		t.Fatalf("Expected the points in 2 parts, received %v", parts)
+	}
+	end, start := parts[0][len(parts[0])-1], parts[1][0]
+	_, endLongitude := end.LatLonDegrees()
+	_, startLongitude := start.LatLonDegrees()
+	if endLongitude != 180 || startLongitude != -180 || end.Latitude != start.Latitude {
+		t.Fatalf("Expected the parts to meet at 180 and -180, received %v and %v", end, start)
+	}
+	// the crossing is on the great circle
+	if xtd := math.Abs(RadiansToNM(CrossTrackError(tokyo, coordKSFO.Coord, end))); xtd > 0.01 {
+		t.Fatalf("Expected the crossing on the great circle, received %v NM off course", xtd)
+	}
+	for _, part := range parts {
+		for i := 1; i < len(part); i++ {
+			if math.Abs(part[i].Longitude-part[i-1].Longitude) > math.Pi {
+				t.Fatalf("Expected no part to cross the antimeridian, received %v", part)
+			}
+		}
+	}
+
+	if result := splitAntimeridian(Densify(coordKSFO.Coord, coordKJFK.Coord, 200)); len(result) != 1 {
+		t.Fatalf("Expected 1 part, received %v", result)
+	}
+	if result := splitAntimeridian(nil); result != nil {
+		t.Fatalf("Expected no parts, received %v", result)
+	}
+}
+
+var finalBearing = []struct {
+	point1   Coordinate
+	point2   Coordinate
+	expected float64
+}{
+	{coordKLAX.Coord, coordKJFK.Coord, DegreesToRadians(93.858)},
+	{coordKJFK.Coord, coordKLAX.Coord, DegreesToRadians(245.892)},
+	// east along the equator
+	{Coordinate{0, 0}, Coordinate{0, DegreesToRadians(-10)}, math.Pi / 2},
+	// north along a meridian, and arriving at the poles
+	{Coordinate{0, 0}, Coordinate{0.5, 0}, 2 * math.Pi},
+	{coordKSFO.Coord, Coordinate{math.Pi / 2, 0}, 2 * math.Pi},
+	{coordKSFO.Coord, Coordinate{-math.Pi / 2, 0}, math.Pi},
+	// coincident points
+	{coordKSFO.Coord, coordKSFO.Coord, 2 * math.Pi},
+}
+
+func TestFinalBearing(t *testing.T) {
+	for _, v := range finalBearing {
+		result := FinalBearing(v.point1, v.point2)
+		if math.Abs(result-v.expected) > 0.001 {
+			t.Fatalf("Expected: %v, received %v", RadiansToDegrees(v.expected), RadiansToDegrees(result))
+		}
+	}
+}
+
+func TestBearingAtDistance(t *testing.T) {
+	distance := Distance(coordKLAX.Coord, coordKJFK.Coord)
+	initial := InitialBearing(coordKLAX.Coord, coordKJFK.Coord)
+	if result := BearingAtDistance(coordKLAX.Coord, coordKJFK.Coord, 0); result != initial {
+		t.Fatalf("Expected: %v, received %v", initial, result)
+	}
+	if result := BearingAtDistance(coordKLAX.Coord, coordKJFK.Coord, distance); math.Abs(result-FinalBearing(coordKLAX.Coord, coordKJFK.Coord)) > 1e-9 {
+		t.Fatalf("Expected: %v, received %v", FinalBearing(coordKLAX.Coord, coordKJFK.Coord), result)
+	}
+	// Clairaut's relation: sin(course) * cos(latitude) is constant along a great circle
+	clairaut := math.Sin(initial) * math.Cos(coordKLAX.Coord.Latitude)
+	radial := Radial{coordKLAX.Coord, initial}
+	for _, along := range []float64{100, 1000, 1800, 3000, 6000} {
+		result := BearingAtDistance(coordKLAX.Coord, coordKJFK.Coord, along)
+		point := coordKLAX.Coord.Destination(initial, along)
+		if math.Abs(math.Sin(result)*math.Cos(point.Latitude)-clairaut) > 1e-9 {
+			t.Fatalf("Expected: %v, received %v", clairaut, math.Sin(result)*math.Cos(point.Latitude))
+		}
+		if radial.BearingAtDistance(along) != result {
+			t.Fatalf("Expected: %v, received %v", result, radial.BearingAtDistance(along))
+		}
+	}
+	// from a pole every course is along the meridian
+	if result := BearingAtDistance(Coordinate{math.Pi / 2, 0}, coordKSFO.Coord, 100); math.Abs(result-math.Pi) > 1e-9 {
+		t.Fatalf("Expected: %v, received %v", math.Pi, result)
+	}
+}
+
 func TestIntersection(t *testing.T) {
 	for _, v := range intersectionRadials {
 		resCoordinate, reserr := IntersectionRadials(v.radial1, v.radial2)
@@ -0,0 +1,278 @@
+package greatcircle
+
+import (
+	"math"
+	"sort"
+)
+
+/*
+Index is an immutable spatial index over a list of points of interest, for
+screening large lists, such as every airport and navaid in the world, against
+routes.
+
+The points are held in a ball tree of unit vectors: each node records a centre
+and the greatest angular distance of its points from that centre. A node whose
+nearest possible point is further from the great circle of a leg than the
+search distance is skipped without testing any of its points, and the points
+that remain are tested exactly as PointsInReach does, so the results are the
+same as the brute force functions.
+
+An Index is safe for concurrent use.
+*/
+type Index struct {
+	coords  []NamedCoordinate
+	vectors [][3]float64
+	// order holds the indexes of coords, arranged so each node covers a contiguous range
+	order []int
+	nodes []indexNode
+}
+
+type indexNode struct {
+	centre [3]float64
+	// radius is the greatest angle in radians between centre and the node's points
+	radius      float64
+	start, end  int
+	left, right int
+}
+
+// indexLeafSize is the most points held by a node that is not split further
+const indexLeafSize = 8
+
+// indexSlack allows for floating point error when pruning, in radians
+const indexSlack = 1e-9
+
+/*
+NewIndex creates an Index over a list of points of interest.
+*/
+func NewIndex(coords []Coordinate) *Index {
+	named := make([]NamedCoordinate, len(coords))
+	for i, coord := range coords {
+		named[i] = coord.ToNamedCoordinate()
+	}
+	return NewNamedIndex(named)
+}
+
+/*
+NewNamedIndex creates an Index over a list of named points of interest.
+*/
+func NewNamedIndex(coords []NamedCoordinate) *Index {
+	index := &Index{
+		coords:  append([]NamedCoordinate(nil), coords...),
+		vectors: make([][3]float64, len(coords)),
+		order:   make([]int, len(coords)),
+	}
+	for i, coord := range coords {
+		index.vectors[i] = unitVector(coord.Coord)
+		index.order[i] = i
+	}
+	if len(coords) > 0 {
+		index.build(0, len(coords))
+	}
+	return index
+}
+
+/*
+Len is the number of points in the Index.
+*/
+func (index *Index) Len() int {
+	return len(index.coords)
+}
+
+/*
+PointsInReach returns the points of the Index within distance nautical miles
+of the (routeStartCoord, routeEndCoord) route. The result is the same as
+PointsInReach with the list the Index was created from.
+*/
+func (index *Index) PointsInReach(routeStartCoord, routeEndCoord Coordinate, distance float64) []Coordinate {
+	results := index.FindPointsInReach(routeStartCoord, routeEndCoord, distance)
+	results.SortByDistance()
+	return results.Coordinates()
+}
+
+/*
+FindPointsInReach returns the points of the Index within distance nautical miles
+of the (routeStartCoord, routeEndCoord) route. The result is the same as
+FindPointsInReach with the list the Index was created from; the Index of each
+result is its position in that list.
+*/
+func (index *Index) FindPointsInReach(routeStartCoord, routeEndCoord Coordinate, distance float64) ReachResults {
+	return index.findPointsInReach(routeStartCoord, routeEndCoord, distance, index.legCandidates(routeStartCoord, routeEndCoord, distance))
+}
+
+// findPointsInReach is FindPointsInReach testing only the points at the candidates indexes
+func (index *Index) findPointsInReach(routeStartCoord, routeEndCoord Coordinate, distance float64, candidates []int) ReachResults {
+	results := ReachResults{}
+	for _, i := range candidates {
+		if result, ok := reachResult(routeStartCoord, routeEndCoord, distance, index.coords[i].Coord); ok {
+			result.Index = i
+			results = append(results, result)
+		}
+	}
+	results.SortByAlongTrack()
+	return results
+}
+
+/*
+MultiPointRoutePOIS returns the points of the Index within distance nautical
+miles of the multi point route. The result is the same as MultiPointRoutePOIS
+with the list the Index was created from.
+*/
+func (index *Index) MultiPointRoutePOIS(routePoints []Coordinate, distance float64, opts ...POIOption) []MultiPoint {
+	return multiPointRoutePOIS(routePoints, distance, newPOIOptions(opts), index.legPointsInReach(distance))
+}
+
+// legPointsInReach finds the points in reach of a leg, nearest first, and the number of candidates tested
+func (index *Index) legPointsInReach(distance float64) func(Coordinate, Coordinate) (ReachResults, int) {
+	return func(routeStartCoord, routeEndCoord Coordinate) (ReachResults, int) {
+		candidates := index.legCandidates(routeStartCoord, routeEndCoord, distance)
+		results := index.findPointsInReach(routeStartCoord, routeEndCoord, distance, candidates)
+		results.SortByDistance()
+		return results, len(candidates)
+	}
+}
+
+/*
+Within returns the points of the Index within distance nautical miles of coord,
+in the order the Index was created from.
+*/
+func (index *Index) Within(coord Coordinate, distance float64) []NamedCoordinate {
+	target := unitVector(coord)
+	limit := NMToRadians(distance)
+	var matches []int
+	index.search(func(node *indexNode) bool {
+		return angleBetween(node.centre, target)-node.radius <= limit+indexSlack
+	}, func(i int) {
+		if Distance(coord, index.coords[i].Coord) <= distance {
+			matches = append(matches, i)
+		}
+	})
+	sort.Ints(matches)
+	result := []NamedCoordinate{}
+	for _, i := range matches {
+		result = append(result, index.coords[i])
+	}
+	return result
+}
+
+/*
+legCandidates returns, in their original order, the indexes of the points that
+may be within distance nautical miles of the great circle through routeStartCoord
+and routeEndCoord.
+*/
+func (index *Index) legCandidates(routeStartCoord, routeEndCoord Coordinate, distance float64) []int {
+	normal := cross(unitVector(routeStartCoord), unitVector(routeEndCoord))
+	length := math.Sqrt(dot(normal, normal))
+	if length < 1e-12 {
+		// coincident or antipodal points do not define a great circle
+		all := make([]int, len(index.coords))
+		for i := range all {
+			all[i] = i
+		}
+		return all
+	}
+	for i := range normal {
+		normal[i] = normal[i] / length
+	}
+	limit := NMToRadians(distance)
+	var matches []int
+	index.search(func(node *indexNode) bool {
+		// no point of the node is nearer the great circle than its centre less its radius
+		return math.Abs(asin(dot(node.centre, normal)))-node.radius <= limit+indexSlack
+	}, func(i int) {
+		if math.Abs(asin(dot(index.vectors[i], normal))) <= limit+indexSlack {
+			matches = append(matches, i)
+		}
+	})
+	sort.Ints(matches)
+	return matches
+}
+
+// search calls visit with each point of the nodes that reach allows
+func (index *Index) search(reach func(*indexNode) bool, visit func(int)) {
+	if len(index.nodes) == 0 {
+		return
+	}
+	stack := []int{0}
+	for len(stack) > 0 {
+		node := &index.nodes[stack[len(stack)-1]]
+		stack = stack[:len(stack)-1]
+		if !reach(node) {
+			continue
+		}
+		if node.left < 0 {
+			for _, i := range index.order[node.start:node.end] {
+				visit(i)
+			}
+			continue
+		}
+		stack = append(stack, node.left, node.right)
+	}
+}
+
+// build adds the node covering order[start:end], and its children, returning its position
+func (index *Index) build(start, end int) int {
+	points := index.order[start:end]
+	var sum, low, high [3]float64
+	for axis := range low {
+		low[axis], high[axis] = math.Inf(1), math.Inf(-1)
+	}
+	for _, i := range points {
+		for axis, value := range index.vectors[i] {
+			sum[axis] += value
+			low[axis] = math.Min(low[axis], value)
+			high[axis] = math.Max(high[axis], value)
+		}
+	}
+	centre := index.vectors[points[0]]
+	if length := math.Sqrt(dot(sum, sum)); length > 1e-9 {
+		centre = [3]float64{sum[0] / length, sum[1] / length, sum[2] / length}
+	}
+	radius := 0.0
+	for _, i := range points {
+		radius = math.Max(radius, angleBetween(centre, index.vectors[i]))
+	}
+
+	position := len(index.nodes)
+	index.nodes = append(index.nodes, indexNode{centre, radius, start, end, -1, -1})
+	if len(points) <= indexLeafSize || radius == 0 {
+		return position
+	}
+	// split at the median of the axis along which the points are most spread
+	axis := 0
+	for a := range low {
+		if high[a]-low[a] > high[axis]-low[axis] {
+			axis = a
+		}
+	}
+	sort.Slice(points, func(i, j int) bool {
+		return index.vectors[points[i]][axis] < index.vectors[points[j]][axis]
+	})
+	middle := start + len(points)/2
+	left := index.build(start, middle)
+	right := index.build(middle, end)
+	index.nodes[position].left, index.nodes[position].right = left, right
+	return position
+}
+
+// unitVector is the position of coord on a sphere of radius 1
+func unitVector(coord Coordinate) [3]float64 {
+	return [3]float64{
+		math.Cos(coord.Latitude) * math.Cos(coord.Longitude),
+		math.Cos(coord.Latitude) * math.Sin(coord.Longitude),
+		math.Sin(coord.Latitude),
+	}
+}
+
+func dot(a, b [3]float64) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+func cross(a, b [3]float64) [3]float64 {
+	return [3]float64{a[1]*b[2] - a[2]*b[1], a[2]*b[0] - a[0]*b[2], a[0]*b[1] - a[1]*b[0]}
+}
+
+// angleBetween is the angle in radians between two unit vectors
+func angleBetween(a, b [3]float64) float64 {
+	c := cross(a, b)
+	return math.Atan2(math.Sqrt(dot(c, c)), dot(a, b))
+}
@@ -0,0 +1,136 @@
+package greatcircle
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// randomCoordinates spreads n coordinates evenly over the Earth
+func randomCoordinates(random *rand.Rand, n int) []Coordinate {
+	coords := make([]Coordinate, n)
+	for i := range coords {
+		coords[i] = Coordinate{math.Asin(2*random.Float64() - 1), math.Pi * (2*random.Float64() - 1)}
+	}
+	return coords
+}
+
+// randomRoute is a route of legs of up to 500 NM, as airways are
+func randomRoute(random *rand.Rand, legs int) []Coordinate {
+	route := randomCoordinates(random, 1)
+	for len(route) <= legs {
+		route = append(route, route[len(route)-1].Destination(2*math.Pi*random.Float64(), 500*random.Float64()))
+	}
+	return route
+}
+
+func TestIndexPointsInReach(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	pois := randomCoordinates(random, 5000)
+	// including points on the route itself and repeated points
+	pois = append(pois, coordKSJC.Coord, coordKMAE.Coord, coordKSFO.Coord, coordKSJC.Coord)
+	index := NewIndex(pois)
+	if index.Len() != len(pois) {
+		t.Fatalf("Expected: %v, received %v", len(pois), index.Len())
+	}
+
+	legs := [][2]Coordinate{
+		{coordKSFO.Coord, coordKLAX.Coord},
+		{coordKLAX.Coord, coordKJFK.Coord},
+		// coincident and antipodal points
+		{coordKSFO.Coord, coordKSFO.Coord},
+		{Coordinate{0, 0}, Coordinate{0, math.Pi}},
+	}
+	for i := 0; i < 50; i++ {
+		route := randomRoute(random, 1)
+		legs = append(legs, [2]Coordinate{route[0], route[1]})
+	}
+	for _, leg := range legs {
+		for _, distance := range []float64{0, 25, 100, 1000} {
+			expected := PointsInReach(leg[0], leg[1], distance, pois)
+			result := index.PointsInReach(leg[0], leg[1], distance)
+			if !reflect.DeepEqual(result, expected) {
+				t.Fatalf("Expected: %v, received %v", expected, result)
+			}
+		}
+	}
+}
+
+func TestIndexMultiPointRoutePOIS(t *testing.T) {
+	random := rand.New(rand.NewSource(2))
+	pois := randomCoordinates(random, 2000)
+	index := NewIndex(pois)
+	for i := 0; i < 5; i++ {
+		route := randomRoute(random, 10)
+		expected := MultiPointRoutePOIS(route, pois, 200)
+		result := index.MultiPointRoutePOIS(route, 200)
+		if !reflect.DeepEqual(result, expected) {
+			t.Fatalf("Expected: %v, received %v", expected, result)
+		}
+	}
+}
+
+func TestIndexWithin(t *testing.T) {
+	index := NewNamedIndex([]NamedCoordinate{coordKSFO, coordKSJC, coordKLAX, coordKJFK, coordKMOD, coordKMAE})
+	result := index.Within(coordKSFO.Coord, 75)
+	expected := []NamedCoordinate{coordKSFO, coordKSJC, coordKMOD}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Expected: %v, received %v", expected, result)
+	}
+
+	random := rand.New(rand.NewSource(3))
+	pois := randomCoordinates(random, 5000)
+	index = NewIndex(pois)
+	for _, coord := range randomCoordinates(random, 20) {
+		count := 0
+		for _, poi := range pois {
+			if Distance(coord, poi) <= 300 {
+				count++
+			}
+		}
+		if result := index.Within(coord, 300); len(result) != count {
+			t.Fatalf("Expected %v points, received %v", count, len(result))
+		}
+	}
+
+	if result := NewIndex(nil).Within(coordKSFO.Coord, 100); len(result) != 0 {
+		t.Fatalf("Expected no points, received %v", result)
+	}
+}
+
+// a world's worth of airports and navaids, and a long route
+var benchmarkPOIS = randomCoordinates(rand.New(rand.NewSource(4)), 70000)
+var benchmarkRoute = randomRoute(rand.New(rand.NewSource(5)), 40)
+
+func BenchmarkPointsInReach(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		for leg := 0; leg+1 < len(benchmarkRoute); leg++ {
+			PointsInReach(benchmarkRoute[leg], benchmarkRoute[leg+1], 50, benchmarkPOIS)
+		}
+	}
+}
+
+func BenchmarkIndexPointsInReach(b *testing.B) {
+	index := NewIndex(benchmarkPOIS)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for leg := 0; leg+1 < len(benchmarkRoute); leg++ {
+			index.PointsInReach(benchmarkRoute[leg], benchmarkRoute[leg+1], 50)
+		}
+	}
+}
+
+func BenchmarkIndexMultiPointRoutePOIS(b *testing.B) {
+	index := NewIndex(benchmarkPOIS)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		index.MultiPointRoutePOIS(benchmarkRoute, 50)
+	}
+}
+
+func BenchmarkNewIndex(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		NewIndex(benchmarkPOIS)
+	}
+}
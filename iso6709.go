@@ -0,0 +1,225 @@
+package greatcircle
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+/*
+ISO6709Format selects how ISO 6709 writes a position: signed latitude and
+longitude, North and East positive, with a fixed number of integer digits and
+a terminating solidus. Degrees, minutes and seconds are run together, and only
+the last unit may have a decimal fraction:
+
+	+37.6167-122.3667/        ISO6709Degrees
+	+3737.00-12222.00/        ISO6709DegreesMinutes
+	+373700.0-1222200.0/      ISO6709DegreesMinutesSeconds
+
+An altitude in metres, and a coordinate reference system, may follow the
+longitude: +37.6167-122.3667+4CRSWGS_84/
+*/
+type ISO6709Format int
+
+const (
+	// ISO6709Degrees writes decimal degrees, ±DD.D±DDD.D/
+	ISO6709Degrees ISO6709Format = iota
+	// ISO6709DegreesMinutes writes degrees and decimal minutes, ±DDMM.M±DDDMM.M/
+	ISO6709DegreesMinutes
+	// ISO6709DegreesMinutesSeconds writes degrees, minutes and decimal seconds, ±DDMMSS.S±DDDMMSS.S/
+	ISO6709DegreesMinutesSeconds
+)
+
+// iso6709Places is the most decimal places written for each format, each a little under a millimetre
+var iso6709Places = []int{9, 7, 5}
+
+/*
+ToISO6709 returns the Coordinate as an ISO 6709 string, such as +37.6167-122.3667/
+
+precision is the number of decimal places of the last unit: degrees, minutes or
+seconds depending on the format. A negative precision writes as many places as
+are needed, to a little under a millimetre, omitting trailing zeros.
+*/
+func (coord Coordinate) ToISO6709(format ISO6709Format, precision int) string {
+	return formatISO6709(coord, nil, format, precision)
+}
+
+/*
+ParseISO6709 parses an ISO 6709 string in any of the ISO6709Format variants.
+Any altitude and coordinate reference system are ignored, and the terminating
+solidus is optional.
+
+Problems are reported as a *ParseError, or a *CoordinateError for a latitude
+beyond a pole.
+*/
+func ParseISO6709(input string) (Coordinate, error) {
+	coord, _, err := parseISO6709(strings.TrimSpace(input))
+	return coord, err
+}
+
+// formatISO6709 writes coord, and any altitude in metres, as ISO 6709
+func formatISO6709(coord Coordinate, altitude *float64, format ISO6709Format, precision int) string {
+	latitude, longitude := coord.LatLonDegrees()
+	text := iso6709Number(latitude, 2, format, precision) + iso6709Number(longitude, 3, format, precision)
+	if altitude != nil {
+		text = text + iso6709Number(*altitude, 1, ISO6709Degrees, -1)
+	}
+	return text + "/"
+}
+
+// iso6709Number writes a signed value in degrees, or metres, with width integer digits of degrees
+func iso6709Number(value float64, width int, format ISO6709Format, precision int) string {
+	if format < ISO6709Degrees || format > ISO6709DegreesMinutesSeconds {
+		format = ISO6709Degrees
+	}
+	places := precision
+	if places < 0 || places > iso6709Places[format] {
+		places = iso6709Places[format]
+	}
+	// round once, in units of the last decimal place, so 59.999 seconds carries into the minutes
+	scale := int64(math.Pow10(places))
+	last := math.Abs(value)
+	for i := ISO6709Degrees; i < format; i++ {
+		last = last * 60
+	}
+	total := int64(math.Round(last * float64(scale)))
+	fraction, whole := total%scale, total/scale
+
+	var subdivisions []int64
+	for i := ISO6709Degrees; i < format; i++ {
+		subdivisions = append([]int64{whole % 60}, subdivisions...)
+		whole = whole / 60
+	}
+
+	sign := "+"
+	if value < 0 && total != 0 {
+		sign = "-"
+	}
+	text := strconv.FormatInt(whole, 10)
+	if len(text) < width {
+		text = strings.Repeat("0", width-len(text)) + text
+	}
+	for _, subdivision := range subdivisions {
+		if subdivision < 10 {
+			text = text + "0"
+		}
+		text = text + strconv.FormatInt(subdivision, 10)
+	}
+	if places > 0 {
+		digits := strconv.FormatInt(fraction, 10)
+		digits = strings.Repeat("0", places-len(digits)) + digits
+		if precision < 0 {
+			digits = strings.TrimRight(digits, "0")
+		}
+		if digits != "" {
+			text = text + "." + digits
+		}
+	}
+	return sign + text
+}
+
+/*
+parseISO6709 parses an ISO 6709 position, and any altitude in metres that
+follows it. A coordinate reference system is ignored.
+*/
+func parseISO6709(input string) (Coordinate, *float64, error) {
+	if len(input) == 0 || (input[0] != '+' && input[0] != '-') {
+		return Coordinate{}, nil, &ParseError{input, 0, ErrUnexpectedCharacter}
+	}
+	latEnd := strings.IndexAny(input[1:], "+-")
+	if latEnd < 0 {
+		return Coordinate{}, nil, &ParseError{input, len(input), ErrMissingValue}
+	}
+	latEnd++
+	lonEnd := len(input)
+	if i := strings.IndexAny(input[latEnd+1:], "+-C/"); i >= 0 {
+		lonEnd = latEnd + 1 + i
+	}
+	latitude, err := parseISO6709Number(input, 0, latEnd, 2)
+	if err != nil {
+		return Coordinate{}, nil, err
+	}
+	longitude, err := parseISO6709Number(input, latEnd, lonEnd, 3)
+	if err != nil {
+		return Coordinate{}, nil, err
+	}
+
+	var altitude *float64
+	end := lonEnd
+	if end < len(input) && (input[end] == '+' || input[end] == '-') {
+		end = len(input)
+		if i := strings.IndexAny(input[lonEnd+1:], "C/"); i >= 0 {
+			end = lonEnd + 1 + i
+		}
+		value, err := strconv.ParseFloat(input[lonEnd:end], 64)
+		if err != nil {
+			return Coordinate{}, nil, &ParseError{input, lonEnd, ErrUnexpectedCharacter}
+		}
+		altitude = &value
+	}
+	if end < len(input) && input[end] == 'C' {
+		if !strings.HasPrefix(input[end:], "CRS") {
+			return Coordinate{}, nil, &ParseError{input, end, ErrUnexpectedCharacter}
+		}
+		crs := end
+		end = len(input)
+		if i := strings.IndexByte(input[crs:], '/'); i >= 0 {
+			end = crs + i
+		}
+	}
+	if end < len(input) && input[end:] != "/" {
+		return Coordinate{}, nil, &ParseError{input, end, ErrUnexpectedCharacter}
+	}
+	coord, err := FromLatLonDegrees(latitude, longitude)
+	return coord, altitude, err
+}
+
+/*
+parseISO6709Number parses the signed input[start:end] as degrees, degrees and
+minutes, or degrees, minutes and seconds with width integer digits of degrees.
+*/
+func parseISO6709Number(input string, start, end, width int) (float64, error) {
+	digits := strings.IndexByte(input[start+1:end], '.')
+	if digits < 0 {
+		digits = end - start - 1
+	}
+	if digits != width && digits != width+2 && digits != width+4 {
+		return 0, &ParseError{input, start + 1, ErrUnexpectedCharacter}
+	}
+	point := start + 1 + digits
+	for i := start + 1; i < end; i++ {
+		if (input[i] < '0' || input[i] > '9') && i != point {
+			return 0, &ParseError{input, i, ErrUnexpectedCharacter}
+		}
+	}
+	if point == end-1 {
+		return 0, &ParseError{input, end, ErrMissingValue}
+	}
+
+	degrees := 0.0
+	unitMultiplier := 1.0
+	for offset := start + 1; offset < point; {
+		portionEnd := offset + 2
+		if offset == start+1 {
+			portionEnd = offset + width
+		}
+		if portionEnd == point {
+			// any decimal fraction belongs to the last unit
+			portionEnd = end
+		}
+		unitValue, err := strconv.ParseFloat(input[offset:portionEnd], 64)
+		if err != nil {
+			return 0, &ParseError{input, offset, ErrUnexpectedCharacter}
+		}
+		if offset > start+1 && unitValue >= 60 {
+			return 0, &ParseError{input, offset, ErrOutOfRange}
+		}
+		degrees = degrees + unitValue/unitMultiplier
+		unitMultiplier = unitMultiplier * 60
+		offset = portionEnd
+	}
+	if input[start] == '-' {
+		degrees = -degrees
+	}
+	return degrees, nil
+}
@@ -0,0 +1,110 @@
+package greatcircle
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestToISO6709(t *testing.T) {
+	ksfo, _ := FromLatLonDegrees(DegreeUnitsToDecimalDegree(37, 37, 0), -DegreeUnitsToDecimalDegree(122, 22, 0))
+	sydney, _ := FromLatLonDegrees(-33.946, 151.177)
+	carry, _ := FromLatLonDegrees(DegreeUnitsToDecimalDegree(10, 0, 59.9999), -DegreeUnitsToDecimalDegree(1, 59, 59.999))
+	nearZero, _ := FromLatLonDegrees(-0.00001, 0.00001)
+	cases := []struct {
+		coord     Coordinate
+		format    ISO6709Format
+		precision int
+		expected  string
+	}{
+		{ksfo, ISO6709Degrees, 4, "+37.6167-122.3667/"},
+		{ksfo, ISO6709DegreesMinutes, 2, "+3737.00-12222.00/"},
+		{ksfo, ISO6709DegreesMinutesSeconds, 1, "+373700.0-1222200.0/"},
+		{ksfo, ISO6709DegreesMinutesSeconds, -1, "+373700-1222200/"},
+		{ksfo, ISO6709Degrees, 0, "+38-122/"},
+		{sydney, ISO6709Degrees, -1, "-33.946+151.177/"},
+		{sydney, ISO6709DegreesMinutes, 1, "-3356.8+15110.6/"},
+		{carry, ISO6709DegreesMinutesSeconds, 2, "+100100.00-0020000.00/"},
+		{nearZero, ISO6709Degrees, 2, "+00.00+000.00/"},
+	}
+	for _, c := range cases {
+		result := c.coord.ToISO6709(c.format, c.precision)
+		if result != c.expected {
+			t.Fatalf("Expected: %s, received %s", c.expected, result)
+		}
+	}
+}
+
+func TestParseISO6709(t *testing.T) {
+	cases := []struct {
+		input               string
+		latitude, longitude float64
+	}{
+		{"+37.6167-122.3667/", 37.6167, -122.3667},
+		{"+3737.00-12222.00/", 37 + 37.0/60, -(122 + 22.0/60)},
+		{"+373700.0-1222200.0/", 37 + 37.0/60, -(122 + 22.0/60)},
+		{"-3356.76+15110.62/", -(33 + 56.76/60), 151 + 10.62/60},
+		{"+40-075/", 40, -75},
+		{"+40.20361-075.00417", 40.20361, -75.00417},
+		{"+27.5916+086.5640+8850CRSWGS_84/", 27.5916, 86.564},
+		{" +00.0+000.0/ ", 0, 0},
+	}
+	for _, c := range cases {
+		result, err := ParseISO6709(c.input)
+		if err != nil {
+			t.Fatalf("Error parsing %q; error %v", c.input, err)
+		}
+		latitude, longitude := result.LatLonDegrees()
+		if math.Abs(latitude-c.latitude) > 1e-9 || math.Abs(longitude-c.longitude) > 1e-9 {
+			t.Fatalf("Expected: %v,%v, received %v,%v from %q", c.latitude, c.longitude, latitude, longitude, c.input)
+		}
+	}
+
+	errorCases := []struct {
+		input    string
+		expected error
+	}{
+		{"37.5-122.5/", ErrUnexpectedCharacter},
+		{"+7.5-122.5/", ErrUnexpectedCharacter},
+		{"+37.5-22.5/", ErrUnexpectedCharacter},
+		{"+3760.00-12222.00/", ErrOutOfRange},
+		{"+37.-122.5/", ErrMissingValue},
+		{"+37.5", ErrMissingValue},
+		{"+37.5-122.5X/", ErrUnexpectedCharacter},
+		{"+37.5-122.5/+", ErrUnexpectedCharacter},
+		{"+95.0+000.0/", ErrInvalidLatitude},
+	}
+	for _, c := range errorCases {
+		_, err := ParseISO6709(c.input)
+		if !errors.Is(err, c.expected) {
+			t.Fatalf("Expected: %v, received %v from %q", c.expected, err, c.input)
+		}
+	}
+}
+
+func TestISO6709RoundTrip(t *testing.T) {
+	coords := []NamedCoordinate{coordKSFO, coordKLAX, coordKJFK, coordKMOD}
+	southEast, _ := FromLatLonDegrees(-89.999999, 179.999999)
+	coords = append(coords, southEast.ToNamedCoordinate())
+	for _, coord := range coords {
+		for _, format := range []ISO6709Format{ISO6709Degrees, ISO6709DegreesMinutes, ISO6709DegreesMinutesSeconds} {
+			text := coord.Coord.ToISO6709(format, -1)
+			results := make([]Coordinate, 3)
+			var err error
+			if results[0], err = ParseISO6709(text); err != nil {
+				t.Fatalf("Error parsing %s; error %v", text, err)
+			}
+			if results[1], err = NewCoordinate(text, ""); err != nil {
+				t.Fatalf("Error parsing %s; error %v", text, err)
+			}
+			if results[2], err = ParseCoordinate(text); err != nil {
+				t.Fatalf("Error parsing %s; error %v", text, err)
+			}
+			for _, result := range results {
+				if math.Abs(result.Latitude-coord.Coord.Latitude) > 1e-10 || math.Abs(result.Longitude-coord.Coord.Longitude) > 1e-10 {
+					t.Fatalf("Expected: %v, received %v from %s", coord.Coord, result, text)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,318 @@
+package greatcircle
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+/*
+KMLOptions controls what WriteKML and WriteKMZ draw.
+*/
+type KMLOptions struct {
+	// Name of the KML document
+	Name string
+	// Spacing is the greatest distance in nautical miles between the points
+	// drawn along each leg; zero uses DefaultKMLSpacing
+	Spacing float64
+	// POIs are drawn as placemarks linked to their nearest point on the
+	// route, for example the results of MultiPointRoutePOIS
+	POIs []MultiPoint
+	// Corridor is the search distance in nautical miles either side of the
+	// route drawn as a shaded polygon along each leg; zero draws no corridor
+	Corridor float64
+}
+
+// DefaultKMLSpacing is the spacing in nautical miles used when KMLOptions.Spacing is zero.
+const DefaultKMLSpacing = 20.0
+
+const kmlNamespace = "http://www.opengis.net/kml/2.2"
+
+type kmlFile struct {
+	XMLName   xml.Name    `xml:"kml"`
+	Namespace string      `xml:"xmlns,attr"`
+	Document  kmlDocument `xml:"Document"`
+}
+
+type kmlDocument struct {
+	Name       string         `xml:"name,omitempty"`
+	Styles     []kmlStyle     `xml:"Style"`
+	Placemarks []kmlPlacemark `xml:"Placemark"`
+}
+
+type kmlStyle struct {
+	ID        string        `xml:"id,attr"`
+	IconStyle *kmlIconStyle `xml:"IconStyle,omitempty"`
+	LineStyle *kmlLineStyle `xml:"LineStyle,omitempty"`
+	PolyStyle *kmlPolyStyle `xml:"PolyStyle,omitempty"`
+}
+
+// KML colours are aabbggrr
+type kmlIconStyle struct {
+	Color string `xml:"color"`
+}
+
+type kmlLineStyle struct {
+	Color string  `xml:"color"`
+	Width float64 `xml:"width"`
+}
+
+type kmlPolyStyle struct {
+	Color   string `xml:"color"`
+	Outline int    `xml:"outline"`
+}
+
+type kmlPlacemark struct {
+	Name        string         `xml:"name,omitempty"`
+	Description string         `xml:"description,omitempty"`
+	StyleURL    string         `xml:"styleUrl,omitempty"`
+	Point       *kmlPoint      `xml:"Point,omitempty"`
+	LineString  *kmlLineString `xml:"LineString,omitempty"`
+	Polygon     *kmlPolygon    `xml:"Polygon,omitempty"`
+	// MultiGeometry holds a line or polygon split where it crosses the antimeridian
+	MultiGeometry *kmlMultiGeometry `xml:"MultiGeometry,omitempty"`
+}
+
+type kmlMultiGeometry struct {
+	LineStrings []kmlLineString `xml:"LineString"`
+	Polygons    []kmlPolygon    `xml:"Polygon"`
+}
+
+type kmlPoint struct {
+	Coordinates string `xml:"coordinates"`
+}
+
+type kmlLineString struct {
+	Tessellate  int    `xml:"tessellate"`
+	Coordinates string `xml:"coordinates"`
+}
+
+type kmlPolygon struct {
+	Tessellate  int    `xml:"tessellate"`
+	Coordinates string `xml:"outerBoundaryIs>LinearRing>coordinates"`
+}
+
+var kmlStyles = []kmlStyle{
+	{ID: "route", LineStyle: &kmlLineStyle{"ff0000ff", 3}},
+	{ID: "waypoint", IconStyle: &kmlIconStyle{"ff0000ff"}},
+	{ID: "poi", IconStyle: &kmlIconStyle{"ff00ffff"}, LineStyle: &kmlLineStyle{"ff00ffff", 1}},
+	{ID: "corridor", LineStyle: &kmlLineStyle{"800000ff", 1}, PolyStyle: &kmlPolyStyle{"400000ff", 1}},
+}
+
+/*
+WriteKML writes the route as a KML document, with every leg a great circle, as
+LegTypedRoute's WriteKML does.
+*/
+func (route MultiPointRoute) WriteKML(w io.Writer, opts KMLOptions) error {
+	return route.WithLegTypes().WriteKML(w, opts)
+}
+
+/*
+WriteKMZ writes the route as WriteKML does, zipped into a KMZ archive.
+*/
+func (route MultiPointRoute) WriteKMZ(w io.Writer, opts KMLOptions) error {
+	return route.WithLegTypes().WriteKMZ(w, opts)
+}
+
+/*
+WriteKML writes the route as a KML document for Google Earth and other
+mapping software.
+
+Each leg is drawn with intermediate points no more than opts.Spacing apart,
+so it follows the great circle or rhumb line rather than a straight chord. A
+leg, or its corridor, crossing the antimeridian is split there, into a
+MultiGeometry of lines or polygons either side.
+The named waypoints and opts.POIs are drawn as placemarks.
+*/
+func (route LegTypedRoute) WriteKML(w io.Writer, opts KMLOptions) error {
+	spacing := opts.Spacing
+	if spacing <= 0 {
+		spacing = DefaultKMLSpacing
+	}
+	document := kmlDocument{Name: opts.Name, Styles: kmlStyles}
+
+	for leg := 0; leg+1 < len(route.Points); leg++ {
+		points := route.DensifyLeg(leg, spacing)
+		if opts.Corridor > 0 {
+			document.Placemarks = append(document.Placemarks, kmlArea(kmlPlacemark{
+				Name:     fmt.Sprintf("%s - %s corridor", route.Points[leg].Name, route.Points[leg+1].Name),
+				StyleURL: "#corridor",
+			}, corridor(points, opts.Corridor)))
+		}
+		document.Placemarks = append(document.Placemarks, kmlLine(kmlPlacemark{
+			Name:        fmt.Sprintf("%s - %s", route.Points[leg].Name, route.Points[leg+1].Name),
+			Description: fmt.Sprintf("%s, %.1f NM", route.LegType(leg), route.legDistance(leg)),
+			StyleURL:    "#route",
+		}, points))
+	}
+	for _, coord := range route.Points {
+		document.Placemarks = append(document.Placemarks, kmlPlacemark{
+			Name:     coord.Name,
+			StyleURL: "#waypoint",
+			Point:    &kmlPoint{kmlCoordinates([]Coordinate{coord.Coord})},
+		})
+	}
+	for _, poi := range opts.POIs {
+		description := fmt.Sprintf("%.1f NM from route", poi.Distance)
+		document.Placemarks = append(document.Placemarks,
+			kmlPlacemark{
+				Description: description,
+				StyleURL:    "#poi",
+				Point:       &kmlPoint{kmlCoordinates([]Coordinate{poi.Poi})},
+			},
+			kmlLine(kmlPlacemark{
+				Description: description,
+				StyleURL:    "#poi",
+			}, []Coordinate{poi.Poi, poi.Neareast}))
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(kmlFile{Namespace: kmlNamespace, Document: document}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+/*
+WriteKMZ writes the route as WriteKML does, zipped into a KMZ archive.
+*/
+func (route LegTypedRoute) WriteKMZ(w io.Writer, opts KMLOptions) error {
+	archive := zip.NewWriter(w)
+	doc, err := archive.Create("doc.kml")
+	if err != nil {
+		return err
+	}
+	if err := route.WriteKML(doc, opts); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+/*
+corridor returns a closed ring of points distance nautical miles either side
+of the line through points.
+*/
+func corridor(points []Coordinate, distance float64) []Coordinate {
+	left := make([]Coordinate, len(points))
+	right := make([]Coordinate, len(points))
+	for i, point := range points {
+		var bearing float64
+		if i+1 < len(points) {
+			bearing = InitialBearing(point, points[i+1])
+		} else {
+			bearing = InitialBearing(point, points[i-1]) + math.Pi
+		}
+		left[i] = point.Destination(bearing-math.Pi/2, distance)
+		right[len(points)-1-i] = point.Destination(bearing+math.Pi/2, distance)
+	}
+	ring := append(left, right...)
+	return append(ring, left[0])
+}
+
+/*
+kmlLine draws the line through points on the placemark, as a LineString, or a
+MultiGeometry of LineStrings split where it crosses the antimeridian.
+*/
+func kmlLine(placemark kmlPlacemark, points []Coordinate) kmlPlacemark {
+	parts := splitAntimeridian(points)
+	if len(parts) == 1 {
+		placemark.LineString = &kmlLineString{1, kmlCoordinates(parts[0])}
+		return placemark
+	}
+	placemark.MultiGeometry = &kmlMultiGeometry{}
+	for _, part := range parts {
+		placemark.MultiGeometry.LineStrings = append(placemark.MultiGeometry.LineStrings, kmlLineString{1, kmlCoordinates(part)})
+	}
+	return placemark
+}
+
+/*
+kmlArea draws the closed ring on the placemark, as a Polygon, or a
+MultiGeometry of Polygons split where it crosses the antimeridian.
+*/
+func kmlArea(placemark kmlPlacemark, ring []Coordinate) kmlPlacemark {
+	parts := splitRingAntimeridian(ring)
+	if len(parts) == 1 {
+		placemark.Polygon = &kmlPolygon{1, kmlCoordinates(parts[0])}
+		return placemark
+	}
+	placemark.MultiGeometry = &kmlMultiGeometry{}
+	for _, part := range parts {
+		placemark.MultiGeometry.Polygons = append(placemark.MultiGeometry.Polygons, kmlPolygon{1, kmlCoordinates(part)})
+	}
+	return placemark
+}
+
+/*
+splitRingAntimeridian clips a closed ring where it crosses the antimeridian
+into closed rings either side, each with the points where its edges cross.
+A ring that does not cross is returned whole.
+*/
+func splitRingAntimeridian(ring []Coordinate) [][]Coordinate {
+	if len(ring) < 2 {
+		return [][]Coordinate{ring}
+	}
+	// follow the ring's longitudes across the antimeridian to find which side it overlaps
+	unwrapped := make([]float64, len(ring))
+	unwrapped[0] = ring[0].Longitude
+	edge := 0.0
+	for i := 1; i < len(ring); i++ {
+		delta := ring[i].Longitude - ring[i-1].Longitude
+		if delta > math.Pi {
+			delta -= 2 * math.Pi
+		} else if delta < -math.Pi {
+			delta += 2 * math.Pi
+		}
+		unwrapped[i] = unwrapped[i-1] + delta
+		if math.Abs(unwrapped[i]) > math.Pi {
+			edge = math.Copysign(math.Pi, unwrapped[i])
+		}
+	}
+	if edge == 0 {
+		return [][]Coordinate{ring}
+	}
+
+	// the side of ring[0], then the far side of the antimeridian
+	var near, far []Coordinate
+	for i := 0; i+1 < len(ring); i++ {
+		from, to := ring[i], ring[i+1]
+		fromNear, toNear := math.Abs(unwrapped[i]) <= math.Pi, math.Abs(unwrapped[i+1]) <= math.Pi
+		if fromNear {
+			near = append(near, from)
+		} else {
+			far = append(far, from)
+		}
+		if fromNear != toNear {
+			latitude := antimeridianLatitude(from, to)
+			// West is positive, so -pi is written as 180 East and pi as 180 West
+			near = append(near, Coordinate{latitude, edge})
+			far = append(far, Coordinate{latitude, -edge})
+		}
+	}
+	parts := [][]Coordinate{}
+	for _, part := range [][]Coordinate{near, far} {
+		if len(part) > 0 {
+			parts = append(parts, append(part, part[0]))
+		}
+	}
+	return parts
+}
+
+// kmlCoordinates formats Coordinates as KML longitude,latitude tuples, East positive
+func kmlCoordinates(coords []Coordinate) string {
+	tuples := make([]string, len(coords))
+	for i, coord := range coords {
+		latitude, longitude := coord.LatLonDegrees()
+		tuples[i] = strconv.FormatFloat(longitude, 'f', 7, 64) + "," + strconv.FormatFloat(latitude, 'f', 7, 64)
+	}
+	return strings.Join(tuples, " ")
+}
@@ -0,0 +1,158 @@
+package greatcircle
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// parseKMLCoordinates reads KML longitude,latitude tuples back into Coordinates
+func parseKMLCoordinates(t *testing.T, tuples string) []Coordinate {
+	var coords []Coordinate
+	for _, tuple := range strings.Fields(tuples) {
+		values := strings.Split(tuple, ",")
+		lon, _ := strconv.ParseFloat(values[0], 64)
+		lat, _ := strconv.ParseFloat(values[1], 64)
+		coord, err := FromLatLonDegrees(lat, lon)
+		if err != nil {
+			t.Fatalf("Error parsing KML coordinates; error %v", err)
+		}
+		coords = append(coords, coord)
+	}
+	return coords
+}
+
+func TestWriteKML(t *testing.T) {
+	route := NewMultiPointRoute([]NamedCoordinate{coordKSFO, coordKLAX, coordKJFK}).WithLegTypes(GreatCircleLeg, RhumbLineLeg)
+	pois := MultiPointRoutePOIS([]Coordinate{coordKSFO.Coord, coordKLAX.Coord}, []Coordinate{coordKSJC.Coord, coordKMAE.Coord}, 50)
+
+	var buffer bytes.Buffer
+	err := route.WriteKML(&buffer, KMLOptions{Name: "KSFO-KJFK", Spacing: 25, POIs: pois, Corridor: 50})
+	if err != nil {
+		t.Fatalf("Error writing KML; error %v", err)
+	}
+	var file kmlFile
+	if err := xml.Unmarshal(buffer.Bytes(), &file); err != nil {
+		t.Fatalf("Error reading KML; error %v", err)
+	}
+	// a corridor and a line per leg, a point per waypoint, a point and a link per POI
+	expected := 2*2 + 3 + 2*len(pois)
+	if file.Document.Name != "KSFO-KJFK" || len(file.Document.Placemarks) != expected {
+		t.Fatalf("Expected %v placemarks, received %v", expected, len(file.Document.Placemarks))
+	}
+
+	lines := []kmlPlacemark{}
+	corridors := []kmlPlacemark{}
+	for _, placemark := range file.Document.Placemarks {
+		if placemark.LineString != nil && placemark.StyleURL == "#route" {
+			lines = append(lines, placemark)
+		}
+		if placemark.Polygon != nil {
+			corridors = append(corridors, placemark)
+		}
+	}
+	if len(lines) != 2 || lines[0].Name != "KSFO - KLAX" || len(corridors) != 2 {
+		t.Fatalf("Expected a line and corridor per leg, received %v and %v", lines, corridors)
+	}
+
+	// the great circle leg follows the great circle
+	points := parseKMLCoordinates(t, lines[0].LineString.Coordinates)
+	if len(points) != int(math.Ceil(Distance(coordKSFO.Coord, coordKLAX.Coord)/25))+1 {
+		t.Fatalf("Expected points no more than 25 NM apart, received %v", len(points))
+	}
+	for _, point := range points {
+		if xtd := math.Abs(RadiansToNM(CrossTrackError(coordKSFO.Coord, coordKLAX.Coord, point))); xtd > 0.01 {
+			t.Fatalf("Expected points on the great circle, received %v NM off course", xtd)
+		}
+	}
+	// the rhumb line leg keeps a constant course
+	points = parseKMLCoordinates(t, lines[1].LineString.Coordinates)
+	course := RhumbBearing(coordKLAX.Coord, coordKJFK.Coord)
+	for i := 1; i+1 < len(points); i++ {
+		if math.Abs(RhumbBearing(coordKLAX.Coord, points[i])-course) > 1e-6 {
+			t.Fatalf("Expected: %v, received %v", course, RhumbBearing(coordKLAX.Coord, points[i]))
+		}
+		if Distance(points[i-1], points[i]) > 25 {
+			t.Fatalf("Expected points no more than 25 NM apart, received %v", Distance(points[i-1], points[i]))
+		}
+	}
+	// the corridor is the search distance either side of the route
+	ring := parseKMLCoordinates(t, corridors[0].Polygon.Coordinates)
+	if !ring[0].Equal(ring[len(ring)-1]) {
+		t.Fatalf("Expected a closed ring, received %v", ring)
+	}
+	for _, point := range ring {
+		if xtd := math.Abs(RadiansToNM(CrossTrackError(coordKSFO.Coord, coordKLAX.Coord, point))); math.Abs(xtd-50) > 0.1 {
+			t.Fatalf("Expected: 50, received %v", xtd)
+		}
+	}
+}
+
+func TestWriteKMLAntimeridian(t *testing.T) {
+	tokyo, _ := FromLatLonDegrees(35.55, 139.78)
+	route := NewMultiPointRoute([]NamedCoordinate{{tokyo, "RJTT"}, coordKSFO})
+	var buffer bytes.Buffer
+	if err := route.WriteKML(&buffer, KMLOptions{Corridor: 50}); err != nil {
+		t.Fatalf("Error writing KML; error %v", err)
+	}
+	var file kmlFile
+	if err := xml.Unmarshal(buffer.Bytes(), &file); err != nil {
+		t.Fatalf("Error reading KML; error %v", err)
+	}
+	area := file.Document.Placemarks[0]
+	if area.Polygon != nil || area.MultiGeometry == nil || len(area.MultiGeometry.Polygons) != 2 {
+		t.Fatalf("Expected the corridor split in 2, received %v", area)
+	}
+	for i, polygon := range area.MultiGeometry.Polygons {
+		ring := parseKMLCoordinates(t, polygon.Coordinates)
+		if !ring[0].Equal(ring[len(ring)-1]) {
+			t.Fatalf("Expected a closed ring, received %v", ring)
+		}
+		for _, point := range ring {
+			// the first ring is East of the antimeridian, the second West
+			if _, longitude := point.LatLonDegrees(); (i == 0) != (longitude > 0) && math.Abs(longitude) != 180 {
+				t.Fatalf("Expected ring %d on one side of the antimeridian, received %v", i, polygon.Coordinates)
+			}
+		}
+	}
+	line := file.Document.Placemarks[1]
+	if line.LineString != nil || line.MultiGeometry == nil || len(line.MultiGeometry.LineStrings) != 2 {
+		t.Fatalf("Expected the leg split in 2, received %v", line)
+	}
+	west, east := line.MultiGeometry.LineStrings[0].Coordinates, line.MultiGeometry.LineStrings[1].Coordinates
+	if !strings.HasSuffix(west, " 180.0000000,"+strings.Split(strings.Fields(east)[0], ",")[1]) || !strings.HasPrefix(east, "-180.0000000,") {
+		t.Fatalf("Expected the lines to meet at 180 and -180, received %v and %v", west, east)
+	}
+}
+
+func TestWriteKMZ(t *testing.T) {
+	route := NewMultiPointRoute([]NamedCoordinate{coordKSFO, coordKLAX})
+	var kml, kmz bytes.Buffer
+	if err := route.WriteKML(&kml, KMLOptions{}); err != nil {
+		t.Fatalf("Error writing KML; error %v", err)
+	}
+	if err := route.WriteKMZ(&kmz, KMLOptions{}); err != nil {
+		t.Fatalf("Error writing KMZ; error %v", err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(kmz.Bytes()), int64(kmz.Len()))
+	if err != nil {
+		t.Fatalf("Error reading KMZ; error %v", err)
+	}
+	if len(archive.File) != 1 || archive.File[0].Name != "doc.kml" {
+		t.Fatalf("Expected doc.kml, received %v", archive.File)
+	}
+	doc, err := archive.File[0].Open()
+	if err != nil {
+		t.Fatalf("Error reading KMZ; error %v", err)
+	}
+	defer doc.Close()
+	result, _ := io.ReadAll(doc)
+	if !bytes.Equal(result, kml.Bytes()) {
+		t.Fatalf("Expected: %s, received %s", kml.Bytes(), result)
+	}
+}
@@ -0,0 +1,296 @@
+package greatcircle
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+The Earth's magnetic field is described by a model of spherical harmonic
+coefficients at an epoch, with their rate of change each year. The World
+Magnetic Model (WMM) is published by NOAA every five years as a .COF file, and
+is accurate to within a degree or so of declination until the next is due.
+*/
+
+//go:embed wmm.cof
+var wmmCOF []byte
+
+/*
+WMM is the World Magnetic Model embedded in the package, WMM-2025, used by
+Declination, MagneticBearing and the magnetic courses of Radials and route
+legs. It is meant for dates from 2025 to 2030, which WMM.ValidAt reports;
+other dates are extrapolated, and grow less accurate. A newer model can be
+loaded with LoadMagneticModel and assigned here.
+*/
+var WMM = mustLoadMagneticModel(wmmCOF)
+
+// ErrMagneticModel is returned when a magnetic model coefficient file cannot be read.
+var ErrMagneticModel = errors.New("invalid magnetic model")
+
+/*
+MagneticModel is a spherical harmonic model of the Earth's magnetic field, such
+as the World Magnetic Model or IGRF, for a single epoch.
+*/
+type MagneticModel struct {
+	Name string
+	// Epoch is the decimal year of the coefficients, such as 2025.0
+	Epoch float64
+	// the Gauss coefficients in nT and their rates of change in nT a year, indexed [n][m]
+	g, h, gDot, hDot [][]float64
+}
+
+const (
+	// magneticLifetime is the number of years from its Epoch a model is meant for
+	magneticLifetime = 5
+	// magneticRadius is the geomagnetic reference radius in km
+	magneticRadius = 6371.2
+	feetPerKm      = 1000 / 0.3048
+)
+
+/*
+LoadMagneticModel reads a model in the .COF format NOAA publishes the WMM in: a
+header line of the epoch and name, then a line "n m g h gDot hDot" for each
+coefficient, ending with a line of 9s. Problems are reported wrapping
+ErrMagneticModel.
+*/
+func LoadMagneticModel(r io.Reader) (*MagneticModel, error) {
+	scanner := bufio.NewScanner(r)
+	model := &MagneticModel{}
+	line := 0
+	fail := func(message string) (*MagneticModel, error) {
+		return nil, fmt.Errorf("greatcircle: line %d: %s: %w", line, message, ErrMagneticModel)
+	}
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if strings.HasPrefix(fields[0], "9999") {
+			break
+		}
+		if model.Name == "" {
+			if len(fields) < 2 {
+				return fail("missing epoch or name")
+			}
+			epoch, err := strconv.ParseFloat(fields[0], 64)
+			if err != nil {
+				return fail("epoch " + strconv.Quote(fields[0]))
+			}
+			model.Epoch, model.Name = epoch, fields[1]
+			continue
+		}
+		if len(fields) < 6 {
+			return fail("missing coefficient")
+		}
+		var values [6]float64
+		for i := range values {
+			value, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				return fail("coefficient " + strconv.Quote(fields[i]))
+			}
+			values[i] = value
+		}
+		n, m := int(values[0]), int(values[1])
+		if n < 1 || m < 0 || m > n || float64(n) != values[0] || float64(m) != values[1] {
+			return fail("degree and order " + fields[0] + " " + fields[1])
+		}
+		for len(model.g) <= n {
+			degree := len(model.g)
+			model.g = append(model.g, make([]float64, degree+1))
+			model.h = append(model.h, make([]float64, degree+1))
+			model.gDot = append(model.gDot, make([]float64, degree+1))
+			model.hDot = append(model.hDot, make([]float64, degree+1))
+		}
+		model.g[n][m], model.h[n][m], model.gDot[n][m], model.hDot[n][m] = values[2], values[3], values[4], values[5]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if model.Name == "" || len(model.g) < 2 {
+		return fail("no coefficients")
+	}
+	return model, nil
+}
+
+func mustLoadMagneticModel(cof []byte) *MagneticModel {
+	model, err := LoadMagneticModel(bytes.NewReader(cof))
+	if err != nil {
+		panic(err)
+	}
+	return model
+}
+
+/*
+ValidAt reports whether the model is meant for date, from its Epoch to five
+years later, when the next is due. Field and Declination extrapolate to other
+dates, and are less accurate the further they are.
+*/
+func (model *MagneticModel) ValidAt(date time.Time) bool {
+	year := decimalYear(date)
+	return year >= model.Epoch && year < model.Epoch+magneticLifetime
+}
+
+/*
+MagneticField is the Earth's magnetic field at a place: its components North,
+East and Down in nanotesla, and its Declination and Inclination in radians.
+Declination, or variation, is the angle of magnetic North East of true North;
+Inclination, or dip, is the angle of the field below the horizontal.
+*/
+type MagneticField struct {
+	North       float64
+	East        float64
+	Down        float64
+	Declination float64
+	Inclination float64
+}
+
+/*
+Field calculates the magnetic field at coord, altitude feet above the WGS84
+ellipsoid, on date. Dates for which the model is not ValidAt are extrapolated.
+*/
+func (model *MagneticModel) Field(coord Coordinate, altitude float64, date time.Time) MagneticField {
+	latitude, longitude := coord.LatLonDegrees()
+	phi, lambda := DegreesToRadians(latitude), DegreesToRadians(longitude)
+	height := altitude / feetPerKm
+	t := decimalYear(date) - model.Epoch
+
+	// geodetic to geocentric spherical coordinates, in km
+	a := WGS84.SemiMajorAxis / 1000
+	e2 := WGS84.Flattening * (2 - WGS84.Flattening)
+	rc := a / math.Sqrt(1-e2*math.Sin(phi)*math.Sin(phi))
+	p := (rc + height) * math.Cos(phi)
+	z := (rc*(1-e2) + height) * math.Sin(phi)
+	r := math.Hypot(p, z)
+	phiPrime := math.Asin(z / r)
+
+	// Schmidt semi-normalised associated Legendre functions of the colatitude, and their derivatives
+	degree := len(model.g) - 1
+	x := math.Sin(phiPrime)
+	s := math.Max(math.Cos(phiPrime), 1e-12)
+	P := make([][]float64, degree+1)
+	dP := make([][]float64, degree+1)
+	for n := range P {
+		P[n] = make([]float64, n+1)
+		dP[n] = make([]float64, n+1)
+	}
+	P[0][0] = 1
+	for n := 1; n <= degree; n++ {
+		if n == 1 {
+			P[1][1], dP[1][1] = s, x
+		} else {
+			k := math.Sqrt(float64(2*n-1) / float64(2*n))
+			P[n][n] = k * s * P[n-1][n-1]
+			dP[n][n] = k * (x*P[n-1][n-1] + s*dP[n-1][n-1])
+		}
+		for m := 0; m < n; m++ {
+			k := math.Sqrt(float64(n*n - m*m))
+			P[n][m] = float64(2*n-1) * x * P[n-1][m] / k
+			dP[n][m] = float64(2*n-1) * (x*dP[n-1][m] - s*P[n-1][m]) / k
+			if n >= 2 && m <= n-2 {
+				j := math.Sqrt(float64((n-1)*(n-1) - m*m))
+				P[n][m] = P[n][m] - j*P[n-2][m]/k
+				dP[n][m] = dP[n][m] - j*dP[n-2][m]/k
+			}
+		}
+	}
+
+	var north, east, down float64
+	ratio := magneticRadius / r
+	scale := ratio * ratio
+	for n := 1; n <= degree; n++ {
+		scale = scale * ratio
+		for m := 0; m <= n; m++ {
+			g := model.g[n][m] + t*model.gDot[n][m]
+			h := model.h[n][m] + t*model.hDot[n][m]
+			cos, sin := math.Cos(float64(m)*lambda), math.Sin(float64(m)*lambda)
+			north = north + scale*(g*cos+h*sin)*dP[n][m]
+			east = east + scale*float64(m)*(g*sin-h*cos)*P[n][m]/s
+			down = down - scale*float64(n+1)*(g*cos+h*sin)*P[n][m]
+		}
+	}
+
+	// back from geocentric to geodetic North and Down
+	psi := phiPrime - phi
+	north, down = north*math.Cos(psi)-down*math.Sin(psi), north*math.Sin(psi)+down*math.Cos(psi)
+	return MagneticField{
+		North:       north,
+		East:        east,
+		Down:        down,
+		Declination: math.Atan2(east, north),
+		Inclination: math.Atan2(down, math.Hypot(north, east)),
+	}
+}
+
+/*
+Declination calculates the magnetic declination, or variation, at coord,
+altitude feet above the WGS84 ellipsoid, on date, in radians East positive.
+*/
+func (model *MagneticModel) Declination(coord Coordinate, altitude float64, date time.Time) float64 {
+	return model.Field(coord, altitude, date).Declination
+}
+
+// decimalYear is the year of date and the fraction of it passed
+func decimalYear(date time.Time) float64 {
+	date = date.UTC()
+	start := time.Date(date.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(1, 0, 0)
+	return float64(date.Year()) + float64(date.Sub(start))/float64(end.Sub(start))
+}
+
+/*
+Declination calculates the magnetic declination at coord, altitude feet above
+the WGS84 ellipsoid, on date, using WMM. Result is in radians, East positive.
+*/
+func Declination(coord Coordinate, altitude float64, date time.Time) float64 {
+	return WMM.Declination(coord, altitude, date)
+}
+
+/*
+MagneticBearing converts a true bearing to magnetic, given the declination,
+both in radians. The result is in (0, 2pi] so that, as for InitialBearing,
+due magnetic North is 2pi.
+*/
+func MagneticBearing(bearing, declination float64) float64 {
+	return normalizeBearing(bearing - declination)
+}
+
+/*
+MagneticBearing returns the Bearing of the Radial as a magnetic bearing, using
+the declination of WMM at its Coordinate at sea level on date.
+*/
+func (radial Radial) MagneticBearing(date time.Time) float64 {
+	return MagneticBearing(radial.Bearing, Declination(radial.Coordinate, 0, date))
+}
+
+/*
+MagneticInitialCourse returns the InitialCourse of the leg as a magnetic
+course, using the declination of WMM at From at sea level on date.
+*/
+func (leg RouteLeg) MagneticInitialCourse(date time.Time) float64 {
+	return MagneticBearing(leg.InitialCourse, Declination(leg.From.Coord, 0, date))
+}
+
+/*
+MagneticFinalCourse returns the FinalCourse of the leg as a magnetic course,
+using the declination of WMM at To at sea level on date.
+*/
+func (leg RouteLeg) MagneticFinalCourse(date time.Time) float64 {
+	return MagneticBearing(leg.FinalCourse, Declination(leg.To.Coord, 0, date))
+}
+
+/*
+MagneticHeading returns the TrueHeading of the leg as a magnetic heading, using
+the declination of WMM at From at the Altitude of the leg on date.
+*/
+func (leg WindLeg) MagneticHeading(date time.Time) float64 {
+	return MagneticBearing(leg.TrueHeading, Declination(leg.From.Coord, leg.Altitude, date))
+}
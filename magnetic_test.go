@@ -0,0 +1,167 @@
+package greatcircle
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// loadTestMagneticModel loads a model from testdata
+func loadTestMagneticModel(t *testing.T, name string) *MagneticModel {
+	file, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("Error opening %v; error %v", name, err)
+	}
+	defer file.Close()
+	model, err := LoadMagneticModel(file)
+	if err != nil {
+		t.Fatalf("Error loading %v; error %v", name, err)
+	}
+	return model
+}
+
+func TestMagneticField(t *testing.T) {
+	// NOAA's published test values for the WMM-2020 coefficients in testdata
+	model := loadTestMagneticModel(t, "WMM2020.COF")
+	cases := []struct {
+		date                time.Time
+		altitude            float64
+		latitude, longitude float64
+		north, east, down   float64
+		declination         float64
+		inclination         float64
+	}{
+		{time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 0, 80, 0, 6570.4, -146.3, 54606.0, -1.28, 83.14},
+		{time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 0, 0, 120, 39624.3, 109.9, -10932.5, 0.16, -15.42},
+		{time.Date(2022, 7, 2, 12, 0, 0, 0, time.UTC), 100000 / 0.3048, 80, 0, 6224.0, -44.5, 52527.0, -0.41, 83.24},
+	}
+	for _, c := range cases {
+		coord, _ := FromLatLonDegrees(c.latitude, c.longitude)
+		result := model.Field(coord, c.altitude, c.date)
+		if math.Abs(result.North-c.north) > 0.1 || math.Abs(result.East-c.east) > 0.1 || math.Abs(result.Down-c.down) > 0.1 {
+			t.Fatalf("Expected: %v,%v,%v, received %v", c.north, c.east, c.down, result)
+		}
+		if math.Abs(RadiansToDegrees(result.Declination)-c.declination) > 0.01 || math.Abs(RadiansToDegrees(result.Inclination)-c.inclination) > 0.01 {
+			t.Fatalf("Expected: %v and %v, received %v and %v", c.declination, c.inclination, RadiansToDegrees(result.Declination), RadiansToDegrees(result.Inclination))
+		}
+	}
+}
+
+func TestWMM(t *testing.T) {
+	if WMM.Name != "WMM-2025" || WMM.Epoch != 2025 {
+		t.Fatalf("Expected: WMM-2025 2025, received %v %v", WMM.Name, WMM.Epoch)
+	}
+	// at 2025.0 the embedded model is within the error of WMM-2020's secular
+	// variation, a few hundred nT, of WMM-2020 brought forward to then; a wrong
+	// low degree coefficient would be out by thousands
+	previous := loadTestMagneticModel(t, "WMM2020.COF")
+	epoch := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for latitude := -80.0; latitude <= 80; latitude = latitude + 20 {
+		for longitude := -180.0; longitude < 180; longitude = longitude + 30 {
+			coord, _ := FromLatLonDegrees(latitude, longitude)
+			result, expected := WMM.Field(coord, 0, epoch), previous.Field(coord, 0, epoch)
+			if math.Abs(result.North-expected.North) > 300 || math.Abs(result.East-expected.East) > 300 || math.Abs(result.Down-expected.Down) > 300 {
+				t.Fatalf("Expected: about %v, received %v at %v", expected, result, coord)
+			}
+		}
+	}
+
+	// about 13 degrees East at San Francisco
+	declination := RadiansToDegrees(Declination(coordKSFO.Coord, 0, time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)))
+	if declination < 12.5 || declination > 14 {
+		t.Fatalf("Expected: about 13, received %v", declination)
+	}
+}
+
+func TestMagneticModelValidAt(t *testing.T) {
+	cases := []struct {
+		date     time.Time
+		expected bool
+	}{
+		{time.Date(2024, 12, 31, 23, 59, 0, 0, time.UTC), false},
+		{time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), true},
+		{time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), true},
+		{time.Date(2029, 12, 31, 23, 59, 0, 0, time.UTC), true},
+		{time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), false},
+	}
+	for _, c := range cases {
+		if result := WMM.ValidAt(c.date); result != c.expected {
+			t.Fatalf("Expected: %v, received %v for %v", c.expected, result, c.date)
+		}
+	}
+}
+
+func TestLoadMagneticModel(t *testing.T) {
+	model, err := LoadMagneticModel(strings.NewReader("    2020.0   TEST   01/01/2020\n  1  0  -30000.0  0.0  10.0  0.0\n999999999999\n"))
+	if err != nil {
+		t.Fatalf("Error loading; error %v", err)
+	}
+	if model.Name != "TEST" || model.Epoch != 2020 {
+		t.Fatalf("Expected: TEST 2020, received %v %v", model.Name, model.Epoch)
+	}
+	// a dipole along the axis has no declination
+	coord, _ := FromLatLonDegrees(45, 45)
+	if result := model.Declination(coord, 0, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)); math.Abs(result) > 1e-12 {
+		t.Fatalf("Expected: 0, received %v", result)
+	}
+
+	for _, cof := range []string{
+		"",
+		"2020.0\n",
+		"2020.0 TEST\n",
+		"2020.0 TEST\n 1 0 -30000.0 0.0 10.0\n",
+		"2020.0 TEST\n 1 2 -30000.0 0.0 10.0 0.0\n",
+		"2020.0 TEST\n 1 0 -30000.0 x 10.0 0.0\n",
+		"X TEST\n 1 0 -30000.0 0.0 10.0 0.0\n",
+	} {
+		if _, err := LoadMagneticModel(strings.NewReader(cof)); !errors.Is(err, ErrMagneticModel) {
+			t.Fatalf("Expected: %v, received %v from %q", ErrMagneticModel, err, cof)
+		}
+	}
+}
+
+func TestMagneticBearing(t *testing.T) {
+	cases := []struct {
+		bearing, declination, expected float64
+	}{
+		{100, 10, 90},
+		{100, -10, 110},
+		{5, 10, 355},
+		{355, -10, 5},
+		{10, 10, 360},
+	}
+	for _, c := range cases {
+		result := MagneticBearing(DegreesToRadians(c.bearing), DegreesToRadians(c.declination))
+		if math.Abs(RadiansToDegrees(result)-c.expected) > 1e-9 {
+			t.Fatalf("Expected: %v, received %v", c.expected, RadiansToDegrees(result))
+		}
+	}
+
+	date := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	radial := Radial{coordKSFO.Coord, DegreesToRadians(280)}
+	expected := MagneticBearing(radial.Bearing, Declination(coordKSFO.Coord, 0, date))
+	if result := radial.MagneticBearing(date); result != expected || result >= radial.Bearing {
+		t.Fatalf("Expected: %v, received %v", expected, result)
+	}
+
+	route := NewMultiPointRoute([]NamedCoordinate{coordKSFO, coordKJFK})
+	leg := route.NavLog().Legs[0]
+	if result, expected := leg.MagneticInitialCourse(date), MagneticBearing(leg.InitialCourse, Declination(coordKSFO.Coord, 0, date)); result != expected {
+		t.Fatalf("Expected: %v, received %v", expected, result)
+	}
+	// variation is East at San Francisco and West at New York
+	if leg.MagneticInitialCourse(date) >= leg.InitialCourse || leg.MagneticFinalCourse(date) <= leg.FinalCourse {
+		t.Fatalf("Expected: less than %v and more than %v, received %v and %v",
+			leg.InitialCourse, leg.FinalCourse, leg.MagneticInitialCourse(date), leg.MagneticFinalCourse(date))
+	}
+
+	windLog, _ := route.WindLog(FlightPlan{TrueAirspeed: 450, Altitudes: []float64{35000}, Winds: Wind{DegreesToRadians(270), 50}})
+	windLeg := windLog.Legs[0]
+	if result, expected := windLeg.MagneticHeading(date), MagneticBearing(windLeg.TrueHeading, Declination(coordKSFO.Coord, 35000, date)); result != expected {
+		t.Fatalf("Expected: %v, received %v", expected, result)
+	}
+}
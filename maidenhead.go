@@ -0,0 +1,134 @@
+package greatcircle
+
+import (
+	"math"
+	"strings"
+)
+
+/*
+A Maidenhead locator, used by radio amateurs, names a cell of a grid by pairs
+of characters, longitude then latitude. The first pair, letters A to R, names a
+field of 20 by 10 degrees; then a square of 2 by 1 degrees in digits; a
+subsquare of 5 by 2.5 minutes in letters a to x; and so on, alternating digits
+and letters:
+
+	CM              field
+	CM87            square
+	CM87wo          subsquare
+	CM87wo12        extended square
+*/
+
+// MaidenheadMaxPrecision is the most pairs of characters in a Maidenhead locator.
+const MaidenheadMaxPrecision = 5
+
+// maidenheadBase is the number of values of each pair of characters of a locator
+func maidenheadBase(pair int) int {
+	switch {
+	case pair == 0:
+		return 18
+	case pair%2 == 1:
+		return 10
+	}
+	return 24
+}
+
+/*
+ToMaidenhead returns the Maidenhead locator of the cell containing the
+Coordinate, of precision pairs of characters from 1 to MaidenheadMaxPrecision:
+"CM87" for a precision of 2, "CM87wo" for 3.
+*/
+func (coord Coordinate) ToMaidenhead(precision int) string {
+	if precision < 1 {
+		precision = 1
+	}
+	if precision > MaidenheadMaxPrecision {
+		precision = MaidenheadMaxPrecision
+	}
+	latitude, longitude := coord.LatLonDegrees()
+	latitude = latitude + 90
+	longitude = math.Mod(longitude+180, 360)
+	if longitude < 0 {
+		longitude = longitude + 360
+	}
+	width, height := 360.0, 180.0
+	var locator strings.Builder
+	for pair := 0; pair < precision; pair++ {
+		base := maidenheadBase(pair)
+		width, height = width/float64(base), height/float64(base)
+		x := clampIndex(int(math.Floor(longitude/width)), base)
+		y := clampIndex(int(math.Floor(latitude/height)), base)
+		longitude = longitude - float64(x)*width
+		latitude = latitude - float64(y)*height
+		first := byte('a')
+		if pair == 0 {
+			first = 'A'
+		} else if pair%2 == 1 {
+			first = '0'
+		}
+		locator.WriteByte(first + byte(x))
+		locator.WriteByte(first + byte(y))
+	}
+	return locator.String()
+}
+
+/*
+DecodeMaidenhead returns the Cell a Maidenhead locator names. Letters may be
+upper or lower case. Problems are reported as a *ParseError, wrapping
+ErrGridReference for a character that is not in a locator.
+*/
+func DecodeMaidenhead(locator string) (Cell, error) {
+	text := strings.TrimSpace(locator)
+	if text == "" || len(text)%2 != 0 {
+		return Cell{}, &ParseError{text, len(text), ErrMissingValue}
+	}
+	if len(text) > 2*MaidenheadMaxPrecision {
+		return Cell{}, &ParseError{text, 2 * MaidenheadMaxPrecision, ErrUnexpectedCharacter}
+	}
+	south, west := -90.0, -180.0
+	width, height := 360.0, 180.0
+	for i := 0; i < len(text); i = i + 2 {
+		pair := i / 2
+		base := maidenheadBase(pair)
+		width, height = width/float64(base), height/float64(base)
+		x := maidenheadValue(text[i], pair)
+		if x < 0 {
+			return Cell{}, &ParseError{text, i, ErrGridReference}
+		}
+		y := maidenheadValue(text[i+1], pair)
+		if y < 0 {
+			return Cell{}, &ParseError{text, i + 1, ErrGridReference}
+		}
+		west = west + float64(x)*width
+		south = south + float64(y)*height
+	}
+	return newCell(south, west, south+height, west+width), nil
+}
+
+// maidenheadValue returns the value of a character of a pair of a locator, or -1
+func maidenheadValue(character byte, pair int) int {
+	value := -1
+	switch {
+	case pair%2 == 1:
+		value = int(character) - '0'
+	case character >= 'a':
+		value = int(character) - 'a'
+	default:
+		value = int(character) - 'A'
+	}
+	if value < 0 || value >= maidenheadBase(pair) {
+		return -1
+	}
+	return value
+}
+
+/*
+NewNamedCoordinateFromMaidenhead creates a NamedCoordinate at the centre of
+the cell a Maidenhead locator names, as DecodeMaidenhead does.
+*/
+func NewNamedCoordinateFromMaidenhead(name, locator string) (NamedCoordinate, error) {
+	cell, err := DecodeMaidenhead(locator)
+	if err != nil {
+		return NamedCoordinate{}, err
+	}
+	return NamedCoordinate{cell.Centre, name}, nil
+}
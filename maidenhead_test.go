@@ -0,0 +1,96 @@
+package greatcircle
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestToMaidenhead(t *testing.T) {
+	origin, _ := FromLatLonDegrees(0, 0)
+	southWest, _ := FromLatLonDegrees(-90, -180)
+	northEast, _ := FromLatLonDegrees(90, 179.99999)
+	cases := []struct {
+		coord     Coordinate
+		precision int
+		expected  string
+	}{
+		{coordKSFO.Coord, 1, "CM"},
+		{coordKSFO.Coord, 2, "CM87"},
+		{coordKSFO.Coord, 3, "CM87to"},
+		{coordKSFO.Coord, 0, "CM"},
+		{origin, 4, "JJ00aa00"},
+		{southWest, 3, "AA00aa"},
+		{northEast, 3, "RR99xx"},
+	}
+	for _, c := range cases {
+		result := c.coord.ToMaidenhead(c.precision)
+		if result != c.expected {
+			t.Fatalf("Expected: %s, received %s", c.expected, result)
+		}
+	}
+	if result := coordKSFO.Coord.ToMaidenhead(9); len(result) != 2*MaidenheadMaxPrecision {
+		t.Fatalf("Expected: %d characters, received %s", 2*MaidenheadMaxPrecision, result)
+	}
+}
+
+func TestDecodeMaidenhead(t *testing.T) {
+	for _, locator := range []string{"CM87wo", "cm87WO", " CM87wo "} {
+		cell, err := DecodeMaidenhead(locator)
+		if err != nil {
+			t.Fatalf("Error decoding %q; error %v", locator, err)
+		}
+		south, west := cell.SouthWest.LatLonDegrees()
+		north, east := cell.NorthEast.LatLonDegrees()
+		if math.Abs(south-(37+35.0/60)) > 1e-9 || math.Abs(west-(-122-10.0/60)) > 1e-9 ||
+			math.Abs(north-(37+37.5/60)) > 1e-9 || math.Abs(east-(-122-5.0/60)) > 1e-9 {
+			t.Fatalf("Expected: 37°35'N 122°10'W to 37°37.5'N 122°5'W, received %v,%v to %v,%v", south, west, north, east)
+		}
+		latitude, longitude := cell.Centre.LatLonDegrees()
+		if math.Abs(latitude-(37+36.25/60)) > 1e-9 || math.Abs(longitude-(-122-7.5/60)) > 1e-9 {
+			t.Fatalf("Expected: 37°36.25'N 122°7.5'W, received %v,%v", latitude, longitude)
+		}
+	}
+
+	errorCases := []struct {
+		locator  string
+		expected error
+	}{
+		{"", ErrMissingValue},
+		{"CM8", ErrMissingValue},
+		{"SM87", ErrGridReference},
+		{"CMA7", ErrGridReference},
+		{"CM87yo", ErrGridReference},
+		{"CM87wo12ab34", ErrUnexpectedCharacter},
+	}
+	for _, c := range errorCases {
+		if _, err := DecodeMaidenhead(c.locator); !errors.Is(err, c.expected) {
+			t.Fatalf("Expected: %v, received %v from %q", c.expected, err, c.locator)
+		}
+	}
+
+	random := rand.New(rand.NewSource(21))
+	for i := 0; i < 1000; i++ {
+		coord, _ := FromLatLonDegrees(random.Float64()*180-90, random.Float64()*360-180)
+		for precision := 1; precision <= MaidenheadMaxPrecision; precision++ {
+			cell, err := DecodeMaidenhead(coord.ToMaidenhead(precision))
+			if err != nil || !cell.Contains(coord) {
+				t.Fatalf("Expected %v to contain %v, %v", cell, coord, err)
+			}
+		}
+	}
+}
+
+func TestNewNamedCoordinateFromMaidenhead(t *testing.T) {
+	result, err := NewNamedCoordinateFromMaidenhead("KSFO", "CM87to")
+	if err != nil {
+		t.Fatalf("Error creating; error %v", err)
+	}
+	if result.Name != "KSFO" || Distance(result.Coord, coordKSFO.Coord) > 2 {
+		t.Fatalf("Expected: %v, received %v", coordKSFO, result)
+	}
+	if _, err := NewNamedCoordinateFromMaidenhead("KSFO", "CM8"); !errors.Is(err, ErrMissingValue) {
+		t.Fatalf("Expected: %v, received %v", ErrMissingValue, err)
+	}
+}
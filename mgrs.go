@@ -0,0 +1,234 @@
+package greatcircle
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+/*
+The Military Grid Reference System (MGRS) names a square of the UTM or UPS
+grid: the zone and latitude band, two letters naming a 100 km square, and an
+even number of digits locating a smaller square within it, from 100 km down to
+1 m:
+
+	31N AA                  100 km
+	31N AA 6 0              10 km
+	31N AA 66021 00000      1 m
+
+The letters use the AA scheme of WGS84. UPS references have no zone, and bands
+A and B in the south, Y and Z in the north.
+*/
+
+const (
+	mgrsBands       = "CDEFGHJKLMNPQRSTUVWX"
+	mgrsRows        = "ABCDEFGHJKLMNPQRSTUV"
+	mgrsSquare      = 100000.0
+	mgrsPrecision   = 5
+	upsBands        = "ABYZ"
+	upsEastBoundary = 20
+)
+
+// mgrsColumns are the column letters of each of the three sets of UTM zones
+var mgrsColumns = []string{"ABCDEFGH", "JKLMNPQR", "STUVWXYZ"}
+
+/*
+upsColumns are the column letters of UPS bands A, B, Y and Z, and upsFirstColumn
+the index of the 100 km square of the first. upsRows and upsFirstRow are the
+same for the rows in the south and the north.
+*/
+var (
+	upsColumns     = []string{"JKLPQRSTUXYZ", "ABCFGHJKLPQR", "RSTUXYZ", "ABCFGHJ"}
+	upsFirstColumn = []int{8, 20, 13, 20}
+	upsRows        = []string{"ABCDEFGHJKLMNPQRSTUVWXYZ", "ABCDEFGHJKLMNP"}
+	upsFirstRow    = []int{8, 13}
+)
+
+/*
+ToMGRS returns the MGRS reference of the square containing the Coordinate,
+such as "31NAA6602100000". precision is the number of digits of each of the
+easting and northing, from 0 for the 100 km square to 5 for a 1 m square.
+
+A *CoordinateError is returned if the Coordinate is not valid.
+*/
+func (coord Coordinate) ToMGRS(precision int) (string, error) {
+	if precision < 0 {
+		precision = 0
+	}
+	if precision > mgrsPrecision {
+		precision = mgrsPrecision
+	}
+	utm, err := coord.ToUTM()
+	if err != nil {
+		return "", err
+	}
+	column := int(math.Floor(utm.Easting / mgrsSquare))
+	row := int(math.Floor(utm.Northing / mgrsSquare))
+
+	var reference string
+	if utm.Zone == 0 {
+		band := 0
+		if utm.North {
+			band = 2
+		}
+		if column >= upsEastBoundary {
+			band++
+		}
+		rows := upsRows[band/2]
+		reference = string(upsBands[band]) +
+			string(upsColumns[band][clampIndex(column-upsFirstColumn[band], len(upsColumns[band]))]) +
+			string(rows[clampIndex(row-upsFirstRow[band/2], len(rows))])
+	} else {
+		latitude, _ := coord.LatLonDegrees()
+		band := clampIndex(int(math.Floor((latitude+80)/8)), len(mgrsBands))
+		columns := mgrsColumns[(utm.Zone-1)%3]
+		row = row % len(mgrsRows)
+		if utm.Zone%2 == 0 {
+			row = (row + 5) % len(mgrsRows)
+		}
+		reference = strconv.Itoa(utm.Zone) + string(mgrsBands[band]) +
+			string(columns[clampIndex(column-1, len(columns))]) + string(mgrsRows[row])
+	}
+
+	scale := math.Pow10(mgrsPrecision - precision)
+	digits := func(metres float64) string {
+		value := int(math.Floor(math.Mod(metres, mgrsSquare) / scale))
+		text := strconv.Itoa(value)
+		return strings.Repeat("0", precision-len(text)) + text
+	}
+	if precision > 0 {
+		reference = reference + digits(utm.Easting) + digits(utm.Northing)
+	}
+	return reference, nil
+}
+
+// clampIndex limits i to the indexes of a list of length n
+func clampIndex(i, n int) int {
+	if i < 0 {
+		return 0
+	}
+	if i >= n {
+		return n - 1
+	}
+	return i
+}
+
+/*
+ParseMGRS parses an MGRS reference at any precision, with or without spaces,
+and returns the UTM or UPS position of the south west corner of its square
+along with the size of the square in metres.
+
+Problems are reported as a *ParseError, wrapping ErrGridReference when the
+letters do not name a square of the zone or band.
+*/
+func ParseMGRS(reference string) (UTM, float64, error) {
+	text := strings.ToUpper(strings.Join(strings.Fields(reference), ""))
+	fail := func(offset int, err error) (UTM, float64, error) {
+		return UTM{}, 0, &ParseError{text, offset, err}
+	}
+
+	i := 0
+	for i < len(text) && i < 2 && unicode.IsDigit(rune(text[i])) {
+		i++
+	}
+	zone := 0
+	if i > 0 {
+		zone, _ = strconv.Atoi(text[:i])
+		if zone < 1 || zone > 60 {
+			return fail(0, ErrGridReference)
+		}
+	}
+	if len(text) < i+3 {
+		return fail(len(text), ErrMissingValue)
+	}
+	bandLetter, columnLetter, rowLetter := text[i], text[i+1], text[i+2]
+	digits := text[i+3:]
+	if len(digits)%2 != 0 || len(digits) > 2*mgrsPrecision {
+		return fail(i+3, ErrUnexpectedCharacter)
+	}
+	for j := range digits {
+		if !unicode.IsDigit(rune(digits[j])) {
+			return fail(i+3+j, ErrUnexpectedCharacter)
+		}
+	}
+	precision := len(digits) / 2
+	size := math.Pow10(mgrsPrecision - precision)
+	var easting, northing float64
+	if precision > 0 {
+		e, _ := strconv.Atoi(digits[:precision])
+		n, _ := strconv.Atoi(digits[precision:])
+		easting, northing = float64(e)*size, float64(n)*size
+	}
+
+	if zone == 0 {
+		band := strings.IndexByte(upsBands, bandLetter)
+		if band < 0 {
+			return fail(i, ErrGridReference)
+		}
+		column := strings.IndexByte(upsColumns[band], columnLetter)
+		if column < 0 {
+			return fail(i+1, ErrGridReference)
+		}
+		row := strings.IndexByte(upsRows[band/2], rowLetter)
+		if row < 0 {
+			return fail(i+2, ErrGridReference)
+		}
+		return UTM{
+			Zone:     0,
+			North:    band >= 2,
+			Easting:  float64(column+upsFirstColumn[band])*mgrsSquare + easting,
+			Northing: float64(row+upsFirstRow[band/2])*mgrsSquare + northing,
+		}, size, nil
+	}
+
+	band := strings.IndexByte(mgrsBands, bandLetter)
+	if band < 0 {
+		return fail(i, ErrGridReference)
+	}
+	column := strings.IndexByte(mgrsColumns[(zone-1)%3], columnLetter)
+	if column < 0 {
+		return fail(i+1, ErrGridReference)
+	}
+	row := strings.IndexByte(mgrsRows, rowLetter)
+	if row < 0 {
+		return fail(i+2, ErrGridReference)
+	}
+	if zone%2 == 0 {
+		row = (row + len(mgrsRows) - 5) % len(mgrsRows)
+	}
+
+	/*
+		The row letters repeat every 2,000 km, and a latitude band is under 1,400 km
+		tall, so the square is the repeat at or above the southern edge of the band.
+		The edge is found on the central meridian, allowing a square for its curve
+		away from it.
+	*/
+	bandLatitude := float64(band)*8 - 80
+	bandCoord, _ := FromLatLonDegrees(bandLatitude, RadiansToDegrees(utmCentralMeridian(zone)))
+	bandSquare := math.Floor(utmForward(bandCoord, zone).Northing/mgrsSquare)*mgrsSquare - mgrsSquare
+	rowNorthing := float64(row) * mgrsSquare
+	for rowNorthing < bandSquare {
+		rowNorthing = rowNorthing + float64(len(mgrsRows))*mgrsSquare
+	}
+	return UTM{
+		Zone:     zone,
+		North:    bandLatitude >= 0,
+		Easting:  float64(column+1)*mgrsSquare + easting,
+		Northing: rowNorthing + northing,
+	}, size, nil
+}
+
+/*
+ParseMGRSCoordinate parses an MGRS reference, as ParseMGRS does, and returns
+the Coordinate of the centre of its square, ready for PointsInReach.
+*/
+func ParseMGRSCoordinate(reference string) (Coordinate, error) {
+	utm, size, err := ParseMGRS(reference)
+	if err != nil {
+		return Coordinate{}, err
+	}
+	utm.Easting = utm.Easting + size/2
+	utm.Northing = utm.Northing + size/2
+	return utm.Coordinate()
+}
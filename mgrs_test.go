@@ -0,0 +1,130 @@
+package greatcircle
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestToMGRS(t *testing.T) {
+	eiffel, _ := FromLatLonDegrees(DegreeUnitsToDecimalDegree(48, 51, 29.5), DegreeUnitsToDecimalDegree(2, 17, 40.2))
+	origin, _ := FromLatLonDegrees(0, 0)
+	sydney, _ := FromLatLonDegrees(-33.946, 151.177)
+	northPole, _ := FromLatLonDegrees(90, 0)
+	southPole, _ := FromLatLonDegrees(-90, 0)
+	svalbard, _ := FromLatLonDegrees(78.2232, 15.6267)
+	cases := []struct {
+		coord     Coordinate
+		precision int
+		expected  string
+	}{
+		{origin, 5, "31NAA6602100000"},
+		{eiffel, 5, "31UDQ4825111932"},
+		{eiffel, 4, "31UDQ48251193"},
+		{eiffel, 2, "31UDQ4811"},
+		{eiffel, 1, "31UDQ41"},
+		{eiffel, 0, "31UDQ"},
+		{eiffel, 9, "31UDQ4825111932"},
+		{sydney, 5, "56HLH3153242334"},
+		{svalbard, 3, "33XWG142833"},
+		{northPole, 5, "ZAH0000000000"},
+		{southPole, 0, "BAN"},
+	}
+	for _, c := range cases {
+		result, err := c.coord.ToMGRS(c.precision)
+		if err != nil {
+			t.Fatalf("Error converting %v; error %v", c.coord, err)
+		}
+		if result != c.expected {
+			t.Fatalf("Expected: %s, received %s", c.expected, result)
+		}
+	}
+}
+
+func TestParseMGRS(t *testing.T) {
+	utm, size, err := ParseMGRS("31N AA 66021 00000")
+	if err != nil {
+		t.Fatalf("Error parsing; error %v", err)
+	}
+	if expected := (UTM{31, true, 166021, 0}); utm != expected || size != 1 {
+		t.Fatalf("Expected: %v, received %v, %v", expected, utm, size)
+	}
+
+	eiffel, _ := FromLatLonDegrees(DegreeUnitsToDecimalDegree(48, 51, 29.5), DegreeUnitsToDecimalDegree(2, 17, 40.2))
+	for _, reference := range []string{"31UDQ4825111932", "31u dq 48251 11932"} {
+		result, err := ParseMGRSCoordinate(reference)
+		if err != nil {
+			t.Fatalf("Error parsing %q; error %v", reference, err)
+		}
+		if Distance(result, eiffel)*metresPerNM > 1 {
+			t.Fatalf("Expected: %v, received %v from %q", eiffel, result, reference)
+		}
+	}
+
+	// the centre of a 10 km square
+	result, err := ParseMGRSCoordinate("31UDQ41")
+	if err != nil {
+		t.Fatalf("Error parsing; error %v", err)
+	}
+	centre, _ := UTM{31, true, 445000, 5415000}.Coordinate()
+	if math.Abs(result.Latitude-centre.Latitude) > 1e-12 || math.Abs(result.Longitude-centre.Longitude) > 1e-12 {
+		t.Fatalf("Expected: %v, received %v", centre, result)
+	}
+
+	errorCases := []struct {
+		reference string
+		expected  error
+	}{
+		{"61NAA", ErrGridReference},
+		{"31NIA", ErrGridReference},
+		{"31NAW", ErrGridReference},
+		{"31OAA", ErrGridReference},
+		{"31NA", ErrMissingValue},
+		{"31NAA123", ErrUnexpectedCharacter},
+		{"31NAA12X4", ErrUnexpectedCharacter},
+		{"CAH", ErrGridReference},
+		{"ZRH", ErrGridReference},
+	}
+	for _, c := range errorCases {
+		_, _, err := ParseMGRS(c.reference)
+		if !errors.Is(err, c.expected) {
+			t.Fatalf("Expected: %v, received %v from %q", c.expected, err, c.reference)
+		}
+	}
+}
+
+func TestMGRSRoundTrip(t *testing.T) {
+	random := rand.New(rand.NewSource(19))
+	for i := 0; i < 2000; i++ {
+		coord, _ := FromLatLonDegrees(math.Asin(random.Float64()*2-1)*180/math.Pi, random.Float64()*360-180)
+		reference, err := coord.ToMGRS(5)
+		if err != nil {
+			t.Fatalf("Error converting %v; error %v", coord, err)
+		}
+		result, err := ParseMGRSCoordinate(reference)
+		if err != nil {
+			t.Fatalf("Error parsing %s; error %v", reference, err)
+		}
+		// within the 1 m square
+		if Distance(coord, result)*metresPerNM > 1 {
+			t.Fatalf("Expected: %v, received %v from %s", coord, result, reference)
+		}
+	}
+}
+
+func TestMGRSPointsInReach(t *testing.T) {
+	var pois []Coordinate
+	for _, coord := range []NamedCoordinate{coordKMOD, coordKJFK} {
+		reference, _ := coord.Coord.ToMGRS(5)
+		poi, err := ParseMGRSCoordinate(reference)
+		if err != nil {
+			t.Fatalf("Error parsing %s; error %v", reference, err)
+		}
+		pois = append(pois, poi)
+	}
+	result := PointsInReach(coordKSFO.Coord, coordKLAX.Coord, 100, pois)
+	if len(result) != 1 || !result[0].Equal(coordKMOD.Coord) {
+		t.Fatalf("Expected: %v, received %v", []Coordinate{coordKMOD.Coord}, result)
+	}
+}
@@ -0,0 +1,75 @@
+package greatcircle
+
+/*
+RouteLeg describes one leg of a MultiPointRoute, from one NamedCoordinate to
+the next, as a line of a navigation log.
+
+Distances are in nautical miles and courses are true, in radians, as
+InitialBearing returns them.
+*/
+type RouteLeg struct {
+	From NamedCoordinate
+	To   NamedCoordinate
+	// Leg is how the leg is travelled; a RhumbLineLeg has a constant course
+	Leg LegType
+	// Distance is the length of the leg, following its LegType
+	Distance      float64
+	InitialCourse float64
+	FinalCourse   float64
+	// CumulativeDistance is the distance from the start of the route to To
+	CumulativeDistance float64
+	// RemainingDistance is the distance from To to the end of the route
+	RemainingDistance float64
+}
+
+/*
+NavLog is the breakdown of a MultiPointRoute into its legs, with the totals
+for the route.
+*/
+type NavLog struct {
+	Legs []RouteLeg
+	// Distance is the total length of the route in nautical miles
+	Distance float64
+}
+
+/*
+NavLog breaks the route down into its legs, each a great circle. A route of
+fewer than two points has no legs.
+*/
+func (route MultiPointRoute) NavLog() NavLog {
+	return route.WithLegTypes().NavLog()
+}
+
+/*
+NavLog breaks the route down into its legs, following each as a great circle
+or rhumb line according to its LegType. A route of fewer than two points has
+no legs.
+*/
+func (route LegTypedRoute) NavLog() NavLog {
+	var navLog NavLog
+	for leg := 0; leg+1 < len(route.Points); leg++ {
+		routeLeg := RouteLeg{
+			From:     route.Points[leg],
+			To:       route.Points[leg+1],
+			Leg:      route.LegType(leg),
+			Distance: route.legDistance(leg),
+		}
+		if routeLeg.Leg == RhumbLineLeg {
+			routeLeg.InitialCourse = RhumbBearing(routeLeg.From.Coord, routeLeg.To.Coord)
+			routeLeg.FinalCourse = routeLeg.InitialCourse
+		} else {
+			routeLeg.InitialCourse = InitialBearing(routeLeg.From.Coord, routeLeg.To.Coord)
+			routeLeg.FinalCourse = FinalBearing(routeLeg.From.Coord, routeLeg.To.Coord)
+		}
+		navLog.Distance = navLog.Distance + routeLeg.Distance
+		routeLeg.CumulativeDistance = navLog.Distance
+		navLog.Legs = append(navLog.Legs, routeLeg)
+	}
+	// summed from the end, so that the last leg has exactly nothing remaining
+	remaining := 0.0
+	for i := len(navLog.Legs) - 1; i >= 0; i-- {
+		navLog.Legs[i].RemainingDistance = remaining
+		remaining = remaining + navLog.Legs[i].Distance
+	}
+	return navLog
+}
@@ -0,0 +1,48 @@
+package greatcircle
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNavLog(t *testing.T) {
+	route := NewMultiPointRoute([]NamedCoordinate{coordKSFO, coordKSJC, coordKLAX}).WithLegTypes(GreatCircleLeg, RhumbLineLeg)
+	navLog := route.NavLog()
+	if len(navLog.Legs) != 2 {
+		t.Fatalf("Expected: 2 legs, received %v", navLog.Legs)
+	}
+	if math.Abs(navLog.Distance-route.Distance()) > 1e-9 {
+		t.Fatalf("Expected: %v, received %v", route.Distance(), navLog.Distance)
+	}
+
+	first := navLog.Legs[0]
+	if first.From != coordKSFO || first.To.Name != "KSJC" || first.Leg != GreatCircleLeg {
+		t.Fatalf("Expected: KSFO to KSJC, received %v", first)
+	}
+	if first.Distance != Distance(coordKSFO.Coord, coordKSJC.Coord) ||
+		first.InitialCourse != InitialBearing(coordKSFO.Coord, coordKSJC.Coord) ||
+		first.FinalCourse != FinalBearing(coordKSFO.Coord, coordKSJC.Coord) {
+		t.Fatalf("Expected the great circle from KSFO to KSJC, received %v", first)
+	}
+	if first.CumulativeDistance != first.Distance || math.Abs(first.RemainingDistance-navLog.Legs[1].Distance) > 1e-9 {
+		t.Fatalf("Expected: %v and %v, received %v and %v", first.Distance, navLog.Legs[1].Distance, first.CumulativeDistance, first.RemainingDistance)
+	}
+
+	second := navLog.Legs[1]
+	if second.From.Name != "KSJC" || second.To.Name != "KLAX" || second.Leg != RhumbLineLeg {
+		t.Fatalf("Expected: KSJC to KLAX, received %v", second)
+	}
+	bearing := RhumbBearing(coordKSJC.Coord, coordKLAX.Coord)
+	if second.Distance != RhumbDistance(coordKSJC.Coord, coordKLAX.Coord) || second.InitialCourse != bearing || second.FinalCourse != bearing {
+		t.Fatalf("Expected the rhumb line from KSJC to KLAX, received %v", second)
+	}
+	if math.Abs(second.CumulativeDistance-navLog.Distance) > 1e-9 || second.RemainingDistance != 0 {
+		t.Fatalf("Expected: %v and 0, received %v and %v", navLog.Distance, second.CumulativeDistance, second.RemainingDistance)
+	}
+
+	for _, route := range []MultiPointRoute{{}, NewMultiPointRoute([]NamedCoordinate{coordKSFO})} {
+		if navLog := route.NavLog(); len(navLog.Legs) != 0 || navLog.Distance != 0 {
+			t.Fatalf("Expected: no legs, received %v", navLog)
+		}
+	}
+}
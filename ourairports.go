@@ -0,0 +1,341 @@
+package greatcircle
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+/*
+OurAirports (https://ourairports.com/data/) publishes the world's airports and
+navaids as CSV files, airports.csv and navaids.csv, each with a header naming
+its columns. The columns are found by name, so files with columns added or
+reordered still load.
+*/
+
+/*
+Facility is an airport or navaid: a NamedCoordinate, named by its identifier
+such as "KSFO" or "SFO", with what is known of it.
+*/
+type Facility struct {
+	NamedCoordinate
+	// Type is the OurAirports type, such as "large_airport", "heliport", "VOR-DME" or "NDB"
+	Type string
+	// Description is the full name, such as "San Francisco International Airport"
+	Description string
+	// Elevation is in feet, or nil if not known
+	Elevation *float64
+	// Country is the ISO 3166-1 code of the country, such as "US"
+	Country string
+	// ICAO and IATA are the codes of an airport, when it has them
+	ICAO   string
+	IATA   string
+	Navaid bool
+}
+
+/*
+FacilityDatabase is an in-memory database of airports and navaids, looked up
+by identifier, ICAO or IATA code. Facilities are held in the order they were
+read, which is the order of Coordinates and NamedCoordinates, so the Index of
+a ReachResult is the position of the Facility.
+
+A FacilityDatabase is safe for concurrent use once it has been read.
+*/
+type FacilityDatabase struct {
+	Facilities []Facility
+	icao       map[string]int
+	iata       map[string]int
+	idents     map[string][]int
+}
+
+/*
+NewFacilityDatabase creates a FacilityDatabase holding facilities, to which
+more can be read.
+*/
+func NewFacilityDatabase(facilities []Facility) *FacilityDatabase {
+	db := &FacilityDatabase{
+		icao:   map[string]int{},
+		iata:   map[string]int{},
+		idents: map[string][]int{},
+	}
+	for _, facility := range facilities {
+		db.add(facility)
+	}
+	return db
+}
+
+/*
+LoadOurAirports reads an OurAirports airports.csv and navaids.csv from disk into
+a new FacilityDatabase. Either file name may be empty to leave it out.
+*/
+func LoadOurAirports(airportsFile, navaidsFile string) (*FacilityDatabase, error) {
+	db := NewFacilityDatabase(nil)
+	for _, load := range []struct {
+		name string
+		read func(io.Reader) error
+	}{{airportsFile, db.ReadAirports}, {navaidsFile, db.ReadNavaids}} {
+		if load.name == "" {
+			continue
+		}
+		file, err := os.Open(load.name)
+		if err != nil {
+			return nil, err
+		}
+		err = load.read(file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", load.name, err)
+		}
+	}
+	return db, nil
+}
+
+/*
+ReadAirports reads an OurAirports airports.csv into the FacilityDatabase. The
+ICAO code is taken from the icao_code column of newer files, or where that is
+missing or empty from a gps_code of four letters.
+
+Problems are reported with the line of the file; a missing column wraps
+ErrMissingValue, and a position that is not valid is a *CoordinateError.
+*/
+func (db *FacilityDatabase) ReadAirports(r io.Reader) error {
+	return readOurAirports(r, func(row ourAirportsRow) error {
+		facility, err := row.facility()
+		if err != nil {
+			return err
+		}
+		if code := row.value("icao_code"); code != "" {
+			facility.ICAO = code
+		} else if code := row.value("gps_code"); isICAO(code) {
+			facility.ICAO = code
+		}
+		facility.IATA = row.value("iata_code")
+		db.add(facility)
+		return nil
+	})
+}
+
+/*
+ReadNavaids reads an OurAirports navaids.csv into the FacilityDatabase. Problems
+are reported as ReadAirports does.
+*/
+func (db *FacilityDatabase) ReadNavaids(r io.Reader) error {
+	return readOurAirports(r, func(row ourAirportsRow) error {
+		facility, err := row.facility()
+		if err != nil {
+			return err
+		}
+		facility.Navaid = true
+		db.add(facility)
+		return nil
+	})
+}
+
+// isICAO reports whether code looks like an ICAO airport code, four letters
+func isICAO(code string) bool {
+	if len(code) != 4 {
+		return false
+	}
+	for i := 0; i < len(code); i++ {
+		if code[i] < 'A' || code[i] > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// add appends facility; the first facility with an ICAO or IATA code keeps it
+func (db *FacilityDatabase) add(facility Facility) {
+	i := len(db.Facilities)
+	db.Facilities = append(db.Facilities, facility)
+	if code := strings.ToUpper(facility.ICAO); code != "" {
+		if _, ok := db.icao[code]; !ok {
+			db.icao[code] = i
+		}
+	}
+	if code := strings.ToUpper(facility.IATA); code != "" {
+		if _, ok := db.iata[code]; !ok {
+			db.iata[code] = i
+		}
+	}
+	ident := strings.ToUpper(facility.Name)
+	db.idents[ident] = append(db.idents[ident], i)
+}
+
+/*
+Len is the number of facilities in the FacilityDatabase.
+*/
+func (db *FacilityDatabase) Len() int {
+	return len(db.Facilities)
+}
+
+/*
+ICAO returns the airport with an ICAO code, such as "KSFO", in any case.
+*/
+func (db *FacilityDatabase) ICAO(code string) (Facility, bool) {
+	i, ok := db.icao[strings.ToUpper(code)]
+	if !ok {
+		return Facility{}, false
+	}
+	return db.Facilities[i], true
+}
+
+/*
+IATA returns the airport with an IATA code, such as "SFO", in any case.
+*/
+func (db *FacilityDatabase) IATA(code string) (Facility, bool) {
+	i, ok := db.iata[strings.ToUpper(code)]
+	if !ok {
+		return Facility{}, false
+	}
+	return db.Facilities[i], true
+}
+
+/*
+Ident returns the facilities with an identifier, in any case. Navaid
+identifiers are not unique, so there may be several.
+*/
+func (db *FacilityDatabase) Ident(ident string) []Facility {
+	var facilities []Facility
+	for _, i := range db.idents[strings.ToUpper(ident)] {
+		facilities = append(facilities, db.Facilities[i])
+	}
+	return facilities
+}
+
+/*
+Filter returns a new FacilityDatabase of the facilities for which keep returns
+true, such as only airports with scheduled service.
+*/
+func (db *FacilityDatabase) Filter(keep func(Facility) bool) *FacilityDatabase {
+	var facilities []Facility
+	for _, facility := range db.Facilities {
+		if keep(facility) {
+			facilities = append(facilities, facility)
+		}
+	}
+	return NewFacilityDatabase(facilities)
+}
+
+/*
+Coordinates returns the Coordinate of each facility, for PointsInReach and
+MultiPointRoutePOIS.
+*/
+func (db *FacilityDatabase) Coordinates() []Coordinate {
+	coords := make([]Coordinate, len(db.Facilities))
+	for i, facility := range db.Facilities {
+		coords[i] = facility.Coord
+	}
+	return coords
+}
+
+/*
+NamedCoordinates returns the NamedCoordinate of each facility.
+*/
+func (db *FacilityDatabase) NamedCoordinates() []NamedCoordinate {
+	coords := make([]NamedCoordinate, len(db.Facilities))
+	for i, facility := range db.Facilities {
+		coords[i] = facility.NamedCoordinate
+	}
+	return coords
+}
+
+/*
+Index creates an Index over the facilities, for screening them against many
+routes.
+*/
+func (db *FacilityDatabase) Index() *Index {
+	return NewNamedIndex(db.NamedCoordinates())
+}
+
+// ourAirportsRow is a row of an OurAirports file, with the columns of its header
+type ourAirportsRow struct {
+	columns map[string]int
+	record  []string
+}
+
+// optional returns the value of a column, and whether the file has the column
+func (row ourAirportsRow) optional(column string) (string, bool) {
+	i, ok := row.columns[column]
+	if !ok || i >= len(row.record) {
+		return "", ok
+	}
+	return strings.TrimSpace(row.record[i]), true
+}
+
+// value returns the value of a column, or "" if the file does not have it
+func (row ourAirportsRow) value(column string) string {
+	value, _ := row.optional(column)
+	return value
+}
+
+// facility reads the columns common to airports and navaids
+func (row ourAirportsRow) facility() (Facility, error) {
+	var values [2]float64
+	for i, column := range []string{"latitude_deg", "longitude_deg"} {
+		text, ok := row.optional(column)
+		if !ok {
+			return Facility{}, fmt.Errorf("column %q: %w", column, ErrMissingValue)
+		}
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return Facility{}, fmt.Errorf("column %q: %w", column, err)
+		}
+		values[i] = value
+	}
+	// an elevation may be left empty, or the column left out, when it is not known
+	var elevation *float64
+	if text := row.value("elevation_ft"); text != "" {
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return Facility{}, fmt.Errorf("column %q: %w", "elevation_ft", err)
+		}
+		elevation = &value
+	}
+	coord, err := FromLatLonDegrees(values[0], values[1])
+	if err != nil {
+		return Facility{}, err
+	}
+	name, ok := row.optional("ident")
+	if !ok {
+		return Facility{}, fmt.Errorf("column %q: %w", "ident", ErrMissingValue)
+	}
+	return Facility{
+		NamedCoordinate: NamedCoordinate{coord, name},
+		Type:            row.value("type"),
+		Description:     row.value("name"),
+		Elevation:       elevation,
+		Country:         row.value("iso_country"),
+	}, nil
+}
+
+// readOurAirports reads the header of an OurAirports file, then calls read with each row
+func readOurAirports(r io.Reader, read func(ourAirportsRow) error) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("greatcircle: reading header: %w", err)
+	}
+	row := ourAirportsRow{columns: map[string]int{}}
+	for i, column := range header {
+		row.columns[strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))] = i
+	}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("greatcircle: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		row.record = record
+		if err := read(row); err != nil {
+			return fmt.Errorf("greatcircle: line %d: %w", line, err)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package greatcircle
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testAirportsCSV = `"id","ident","type","name","latitude_deg","longitude_deg","elevation_ft","continent","iso_country","iso_region","municipality","scheduled_service","gps_code","iata_code","local_code","home_link","wikipedia_link","keywords"
+3878,"KSFO","large_airport","San Francisco International Airport",37.61899948120117,-122.375,13,"NA","US","US-CA","San Francisco","yes","KSFO","SFO","SFO","https://www.flysfo.com/","https://en.wikipedia.org/wiki/San_Francisco_International_Airport","QSF, QBA"
+3632,"KLAX","large_airport","Los Angeles International Airport",33.942501,-118.407997,125,"NA","US","US-CA","Los Angeles","yes","KLAX","LAX","LAX",,,
+3780,"KMOD","medium_airport","Modesto City Co-Harry Sham Field",37.625499725341794,-120.95400238037108,97,"NA","US","US-CA","Modesto","no","KMOD","MOD","MOD",,,
+3622,"KJFK","large_airport","John F Kennedy International Airport",40.639801,-73.7789,13,"NA","US","US-NY","New York","yes","KJFK","JFK","JFK",,,
+6523,"00A","heliport","Total Rf Heliport",40.070985,-74.933689,11,"NA","US","US-PA","Bensalem","no","K00A",,"00A",,,
+`
+
+const testNavaidsCSV = `"id","filename","ident","name","type","frequency_khz","latitude_deg","longitude_deg","elevation_ft","iso_country","dme_frequency_khz","dme_channel","dme_latitude_deg","dme_longitude_deg","dme_elevation_ft","slaved_variation_deg","magnetic_variation_deg","usageType","power","associated_airport"
+85934,"San_Francisco_VOR-DME_US","SFO","San Francisco","VOR-DME",115800,37.61949920654297,-122.37399673461914,13,"US",1115800,"105X",,,,17,13.77,"BOTH","HIGH","KSFO"
+90000,"Sofala_NDB_MZ","SFO","Sofala","NDB",350,-19.8,34.8,,"MZ",,,,,,,,"BOTH","LOW",
+`
+
+func TestReadOurAirports(t *testing.T) {
+	db := NewFacilityDatabase(nil)
+	if err := db.ReadAirports(strings.NewReader(testAirportsCSV)); err != nil {
+		t.Fatalf("Error reading airports; error %v", err)
+	}
+	if err := db.ReadNavaids(strings.NewReader(testNavaidsCSV)); err != nil {
+		t.Fatalf("Error reading navaids; error %v", err)
+	}
+	if db.Len() != 7 {
+		t.Fatalf("Expected: 7, received %v", db.Len())
+	}
+
+	ksfo, ok := db.ICAO("ksfo")
+	if !ok {
+		t.Fatalf("Expected KSFO")
+	}
+	expected := Facility{
+		NamedCoordinate: ksfo.NamedCoordinate,
+		Type:            "large_airport",
+		Description:     "San Francisco International Airport",
+		Elevation:       ksfo.Elevation,
+		Country:         "US",
+		ICAO:            "KSFO",
+		IATA:            "SFO",
+	}
+	if ksfo != expected || ksfo.Name != "KSFO" || Distance(ksfo.Coord, coordKSFO.Coord) > 0.5 {
+		t.Fatalf("Expected: %v, received %v", expected, ksfo)
+	}
+	if ksfo.Elevation == nil || *ksfo.Elevation != 13 {
+		t.Fatalf("Expected: an elevation of 13, received %v", ksfo.Elevation)
+	}
+	if result, ok := db.IATA("sfo"); !ok || result != ksfo {
+		t.Fatalf("Expected: %v, received %v", ksfo, result)
+	}
+	if _, ok := db.ICAO("K00A"); ok {
+		t.Fatalf("Expected no ICAO code for a gps_code with digits")
+	}
+	if _, ok := db.IATA("XXX"); ok {
+		t.Fatalf("Expected no XXX")
+	}
+
+	navaids := db.Ident("SFO")
+	if len(navaids) != 2 || !navaids[0].Navaid || navaids[0].Type != "VOR-DME" || navaids[1].Country != "MZ" || navaids[1].Elevation != nil {
+		t.Fatalf("Expected: the SFO VOR-DME and NDB, received %v", navaids)
+	}
+	if result := db.Ident("klax"); len(result) != 1 || result[0].IATA != "LAX" {
+		t.Fatalf("Expected: KLAX, received %v", result)
+	}
+
+	airports := db.Filter(func(facility Facility) bool { return !facility.Navaid })
+	if airports.Len() != 5 || len(airports.Ident("SFO")) != 0 {
+		t.Fatalf("Expected: 5 airports, received %v", airports.Facilities)
+	}
+	if result, ok := airports.IATA("JFK"); !ok || result.Name != "KJFK" {
+		t.Fatalf("Expected: KJFK, received %v", result)
+	}
+}
+
+func TestFacilityDatabasePointsInReach(t *testing.T) {
+	db := NewFacilityDatabase(nil)
+	db.ReadAirports(strings.NewReader(testAirportsCSV))
+	ksfo, _ := db.ICAO("KSFO")
+	klax, _ := db.ICAO("KLAX")
+
+	results := FindPointsInReach(ksfo.Coord, klax.Coord, 100, db.Coordinates())
+	var names []string
+	for _, result := range results {
+		names = append(names, db.Facilities[result.Index].Name)
+	}
+	if strings.Join(names, " ") != "KSFO KMOD KLAX" {
+		t.Fatalf("Expected: KSFO KMOD KLAX, received %v", names)
+	}
+
+	coords := db.Coordinates()
+	pois := PointsInReach(ksfo.Coord, klax.Coord, 100, coords)
+	if result := db.Index().PointsInReach(ksfo.Coord, klax.Coord, 100); len(result) != len(pois) || len(pois) != 3 {
+		t.Fatalf("Expected: %v, received %v", pois, result)
+	}
+
+	route := []Coordinate{ksfo.Coord, klax.Coord}
+	if result := MultiPointRoutePOIS(route, coords, 100); len(result) != 3 {
+		t.Fatalf("Expected: 3, received %v", result)
+	}
+	if named := db.NamedCoordinates(); len(named) != db.Len() || named[2].Name != "KMOD" || named[2].Coord != coords[2] {
+		t.Fatalf("Expected: KMOD, received %v", named)
+	}
+}
+
+func TestReadOurAirportsErrors(t *testing.T) {
+	// newer files have an icao_code column, which may be empty where gps_code is not
+	db := NewFacilityDatabase(nil)
+	err := db.ReadAirports(strings.NewReader("ident,type,latitude_deg,longitude_deg,gps_code,icao_code\n" +
+		"KSFO,large_airport,37.619,-122.375,KSFO,\n" +
+		"SCIP,medium_airport,-27.165,-109.422,SCIP,SCIP\n" +
+		"US-0001,small_airport,40.1,-100.2,1CA2,\n" +
+		"OOMS,large_airport,23.593,58.284,,OOMS\n"))
+	if err != nil {
+		t.Fatalf("Error reading airports; error %v", err)
+	}
+	for i, expected := range []string{"KSFO", "SCIP", "", "OOMS"} {
+		if result := db.Facilities[i].ICAO; result != expected {
+			t.Fatalf("Expected: %q, received %q", expected, result)
+		}
+	}
+	if result := db.Facilities[0].Elevation; result != nil {
+		t.Fatalf("Expected no elevation, received %v", *result)
+	}
+
+	// an empty elevation is not known, but zero is sea level
+	db = NewFacilityDatabase(nil)
+	err = db.ReadAirports(strings.NewReader("ident,type,latitude_deg,longitude_deg,elevation_ft\nEGLL,large_airport,51.47,-0.46,\nEHAM,large_airport,52.31,4.76,0\n"))
+	if err != nil {
+		t.Fatalf("Error reading airports; error %v", err)
+	}
+	if result := db.Facilities[0].Elevation; result != nil {
+		t.Fatalf("Expected no elevation, received %v", *result)
+	}
+	if result := db.Facilities[1].Elevation; result == nil || *result != 0 {
+		t.Fatalf("Expected: an elevation of 0, received %v", result)
+	}
+
+	cases := []struct {
+		csv      string
+		expected error
+	}{
+		{"ident,latitude_deg\nKSFO,37.619\n", ErrMissingValue},
+		{"latitude_deg,longitude_deg\n37.619,-122.375\n", ErrMissingValue},
+		{"ident,latitude_deg,longitude_deg\nKSFO,97.619,-122.375\n", ErrInvalidLatitude},
+	}
+	for _, c := range cases {
+		if err := NewFacilityDatabase(nil).ReadAirports(strings.NewReader(c.csv)); !errors.Is(err, c.expected) {
+			t.Fatalf("Expected: %v, received %v from %q", c.expected, err, c.csv)
+		}
+	}
+	err = NewFacilityDatabase(nil).ReadNavaids(strings.NewReader("ident,latitude_deg,longitude_deg\nSFO,37.619,-122.375\nOAK,north,-122.2\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Fatalf("Expected an error on line 3, received %v", err)
+	}
+	if err := NewFacilityDatabase(nil).ReadNavaids(strings.NewReader("")); err == nil {
+		t.Fatalf("Expected an error for no header")
+	}
+}
+
+func TestLoadOurAirports(t *testing.T) {
+	dir := t.TempDir()
+	airports := filepath.Join(dir, "airports.csv")
+	navaids := filepath.Join(dir, "navaids.csv")
+	os.WriteFile(airports, []byte(testAirportsCSV), 0o644)
+	os.WriteFile(navaids, []byte(testNavaidsCSV), 0o644)
+
+	db, err := LoadOurAirports(airports, navaids)
+	if err != nil {
+		t.Fatalf("Error loading; error %v", err)
+	}
+	if db.Len() != 7 {
+		t.Fatalf("Expected: 7, received %v", db.Len())
+	}
+	db, err = LoadOurAirports(airports, "")
+	if err != nil || db.Len() != 5 {
+		t.Fatalf("Expected: 5 airports, received %v, %v", db, err)
+	}
+	if _, err := LoadOurAirports(filepath.Join(dir, "missing.csv"), ""); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Expected: %v, received %v", os.ErrNotExist, err)
+	}
+}
@@ -0,0 +1,271 @@
+package greatcircle
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Errors wrapped by ParseError describing why a string could not be parsed.
+var (
+	ErrUnexpectedCharacter = errors.New("unexpected character")
+	ErrHemisphere          = errors.New("hemisphere not valid here")
+	ErrTooManyParts        = errors.New("should have 3 or fewer portions")
+	ErrMissingValue        = errors.New("missing value")
+	ErrOutOfRange          = errors.New("minutes and seconds must be less than 60")
+	ErrAmbiguous           = errors.New("cannot tell latitude from longitude")
+)
+
+/*
+ParseError describes why a degree or coordinate string could not be parsed,
+and the offending character.
+*/
+type ParseError struct {
+	// Input is the complete string being parsed
+	Input string
+	// Offset is the byte offset of the offending character within Input
+	Offset int
+	// Err is one of the Err* values above, or ErrInvalidLatitude or
+	// ErrInvalidLongitude for degrees beyond 90 or 180
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	if e.Offset >= len(e.Input) {
+		return fmt.Sprintf("greatcircle: parsing %q: %v at end of input", e.Input, e.Err)
+	}
+	r, _ := utf8.DecodeRuneInString(e.Input[e.Offset:])
+	return fmt.Sprintf("greatcircle: parsing %q: %v at offset %d (%q)", e.Input, e.Err, e.Offset, r)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+/*
+ParseCoordinate parses a latitude and longitude written together in one of
+the common human formats, applying the sign from the N/S/E/W letters:
+
+	37°37'00"N 122°22'00"W
+	N37 37.0 W122 22.0
+	37-37-00N 122-22-00W
+	37.6167N 122.3667W
+	3737N12222W
+	373700N1222200W
+	+37.6167-122.3667/
+
+Without hemisphere letters the latitude and longitude must be separated by
+a comma, and are signed following this library's convention (West positive),
+unless the coordinate is an ISO 6709 string; see ParseISO6709.
+
+The result is normalised. More than 90 degrees of latitude or 180 of longitude
+is reported as a *ParseError wrapping ErrInvalidLatitude or ErrInvalidLongitude.
+*/
+func ParseCoordinate(coordinate string) (Coordinate, error) {
+	if trimmed := strings.TrimSpace(coordinate); trimmed != "" && strings.ContainsRune("+-", rune(trimmed[0])) && !strings.ContainsRune(trimmed, ',') {
+		return ParseISO6709(trimmed)
+	}
+	latStart, latEnd, lonStart, lonEnd, err := splitCoordinate(coordinate)
+	if err != nil {
+		return Coordinate{}, err
+	}
+	latitude, err := parseAngle(coordinate, latStart, latEnd, latitudeAxis)
+	if err != nil {
+		return Coordinate{}, err
+	}
+	longitude, err := parseAngle(coordinate, lonStart, lonEnd, longitudeAxis)
+	if err != nil {
+		return Coordinate{}, err
+	}
+	return NewCoordinateDegrees(latitude, longitude)
+}
+
+/*
+splitCoordinate finds the latitude and longitude portions of a combined
+coordinate string, using the hemisphere letters or a comma.
+*/
+func splitCoordinate(coordinate string) (latStart, latEnd, lonStart, lonEnd int, err error) {
+	latLetter, lonLetter := -1, -1
+	for i, r := range coordinate {
+		switch unicode.ToUpper(r) {
+		case 'N', 'S':
+			if latLetter >= 0 {
+				return 0, 0, 0, 0, &ParseError{coordinate, i, ErrHemisphere}
+			}
+			latLetter = i
+		case 'E', 'W':
+			if lonLetter >= 0 {
+				return 0, 0, 0, 0, &ParseError{coordinate, i, ErrHemisphere}
+			}
+			lonLetter = i
+		}
+	}
+
+	switch {
+	case latLetter >= 0 && lonLetter >= 0:
+		first, second := latLetter, lonLetter
+		if second < first {
+			first, second = second, first
+		}
+		split := first + 1
+		if strings.TrimSpace(coordinate[:first]) == "" {
+			// hemisphere letters prefix each portion
+			split = second
+		}
+		if latLetter < lonLetter {
+			return 0, split, split, len(coordinate), nil
+		}
+		return split, len(coordinate), 0, split, nil
+	case latLetter >= 0:
+		// a latitude hemisphere without a longitude hemisphere
+		return 0, 0, 0, 0, &ParseError{coordinate, latLetter, ErrHemisphere}
+	case lonLetter >= 0:
+		return 0, 0, 0, 0, &ParseError{coordinate, lonLetter, ErrHemisphere}
+	}
+
+	comma := strings.IndexByte(coordinate, ',')
+	if comma < 0 {
+		return 0, 0, 0, 0, &ParseError{coordinate, 0, ErrAmbiguous}
+	}
+	return 0, comma, comma + 1, len(coordinate), nil
+}
+
+type angleAxis int
+
+const (
+	anyAxis angleAxis = iota
+	latitudeAxis
+	longitudeAxis
+)
+
+/*
+parseAngle parses input[start:end] as a single latitude or longitude in degrees.
+
+Offsets in any ParseError are relative to the complete input.
+*/
+func parseAngle(input string, start, end int, axis angleAxis) (float64, error) {
+	type portion struct {
+		text   string
+		offset int
+	}
+	var portions []portion
+	sign := 1.0
+	signOffset, hemisphereOffset := -1, -1
+	suffixed := false
+
+	for i := start; i < end; {
+		r, size := utf8.DecodeRuneInString(input[i:end])
+		switch {
+		case suffixed && !unicode.IsSpace(r):
+			if i == hemisphereOffset+1 && (r >= '0' && r <= '9' || r == '.') {
+				// a letter within a number, such as 1e3, is the offending character
+				return 0, &ParseError{input, hemisphereOffset, ErrUnexpectedCharacter}
+			}
+			return 0, &ParseError{input, i, ErrUnexpectedCharacter}
+		case r >= '0' && r <= '9' || r == '.':
+			j := i
+			for j < end && (input[j] >= '0' && input[j] <= '9' || input[j] == '.') {
+				j++
+			}
+			if len(portions) == 3 {
+				return 0, &ParseError{input, i, ErrTooManyParts}
+			}
+			portions = append(portions, portion{input[i:j], i})
+			i = j
+			continue
+		case (r == '+' || r == '-') && len(portions) == 0:
+			if signOffset >= 0 || hemisphereOffset >= 0 {
+				return 0, &ParseError{input, i, ErrUnexpectedCharacter}
+			}
+			signOffset = i
+			if r == '-' {
+				sign = -1
+			}
+		case r == '-' || r == ':' || unicode.IsSpace(r) || strings.ContainsRune("°º'\"′″", r):
+			// separator
+		case strings.ContainsRune("NSEWnsew", r):
+			hemisphere := unicode.ToUpper(r)
+			latitudeLetter := hemisphere == 'N' || hemisphere == 'S'
+			if hemisphereOffset >= 0 || signOffset >= 0 ||
+				(latitudeLetter && axis == longitudeAxis) || (!latitudeLetter && axis == latitudeAxis) {
+				return 0, &ParseError{input, i, ErrHemisphere}
+			}
+			hemisphereOffset = i
+			suffixed = len(portions) > 0
+			if latitudeLetter {
+				axis = latitudeAxis
+			} else {
+				axis = longitudeAxis
+			}
+			if hemisphere == 'S' || hemisphere == 'E' {
+				sign = -1
+			}
+		default:
+			return 0, &ParseError{input, i, ErrUnexpectedCharacter}
+		}
+		i += size
+	}
+
+	if len(portions) == 0 {
+		return 0, &ParseError{input, end, ErrMissingValue}
+	}
+	for i, p := range portions {
+		if dot := strings.IndexByte(p.text, '.'); dot >= 0 {
+			if i != len(portions)-1 {
+				return 0, &ParseError{input, p.offset + dot, ErrUnexpectedCharacter}
+			}
+			if second := strings.IndexByte(p.text[dot+1:], '.'); second >= 0 {
+				return 0, &ParseError{input, p.offset + dot + 1 + second, ErrUnexpectedCharacter}
+			}
+		}
+	}
+
+	// packed degrees, minutes and seconds: 3737N, 373700N, 1222200W
+	if len(portions) == 1 && axis != anyAxis {
+		width := 2
+		if axis == longitudeAxis {
+			width = 3
+		}
+		p := portions[0]
+		digits := strings.IndexByte(p.text, '.')
+		if digits < 0 {
+			digits = len(p.text)
+		}
+		if digits == width+2 || digits == width+4 {
+			portions = []portion{{p.text[:width], p.offset}}
+			for offset := width; offset < digits; offset += 2 {
+				portionEnd := offset + 2
+				if portionEnd == digits {
+					// any decimal fraction belongs to the last portion
+					portionEnd = len(p.text)
+				}
+				portions = append(portions, portion{p.text[offset:portionEnd], p.offset + offset})
+			}
+		}
+	}
+
+	unitMultiplier := 1.0
+	decimalDegree := 0.0
+	for i, p := range portions {
+		unitValue, err := strconv.ParseFloat(p.text, 64)
+		if err != nil {
+			return 0, &ParseError{input, p.offset, ErrUnexpectedCharacter}
+		}
+		if i > 0 && unitValue >= 60 {
+			return 0, &ParseError{input, p.offset, ErrOutOfRange}
+		}
+		decimalDegree = decimalDegree + unitValue/unitMultiplier
+		unitMultiplier = unitMultiplier * 60
+	}
+	// a latitude is at most 90 degrees, and a longitude, or an angle that could be either, 180
+	if axis == latitudeAxis && decimalDegree > 90 {
+		return 0, &ParseError{input, portions[0].offset, ErrInvalidLatitude}
+	}
+	if decimalDegree > 180 {
+		return 0, &ParseError{input, portions[0].offset, ErrInvalidLongitude}
+	}
+	return sign * decimalDegree, nil
+}
@@ -0,0 +1,165 @@
+package greatcircle
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+var degreeStrings = []struct {
+	degrees  string
+	expected float64
+}{
+	{"37:37:00", DegreeUnitsToDecimalDegree(37, 37, 0)},
+	{"37:5", DegreeUnitsToDecimalDegree(37, 5, 0)},
+	{"-37:30", -37.5},
+	{"37°37'00\"N", DegreeUnitsToDecimalDegree(37, 37, 0)},
+	{"37°37′00″S", -DegreeUnitsToDecimalDegree(37, 37, 0)},
+	{"N37 37.0", DegreeUnitsToDecimalDegree(37, 37, 0)},
+	{"122-22-00W", DegreeUnitsToDecimalDegree(122, 22, 0)},
+	{"122-22-00e", -DegreeUnitsToDecimalDegree(122, 22, 0)},
+	{"37.6167N", 37.6167},
+	{"3737N", DegreeUnitsToDecimalDegree(37, 37, 0)},
+	{"3737.5N", DegreeUnitsToDecimalDegree(37, 37.5, 0)},
+	{"12222W", DegreeUnitsToDecimalDegree(122, 22, 0)},
+	{"373700N", DegreeUnitsToDecimalDegree(37, 37, 0)},
+	{"1222200W", DegreeUnitsToDecimalDegree(122, 22, 0)},
+	{"0012030E", -DegreeUnitsToDecimalDegree(1, 20, 30)},
+	{"90N", 90},
+	{"180:00:00E", -180},
+	{"1800000W", 180},
+}
+
+func TestDegreeStrToDecimalDegreeFormats(t *testing.T) {
+	for _, v := range degreeStrings {
+		result, err := DegreeStrToDecimalDegree(v.degrees)
+		if err != nil {
+			t.Fatalf("Error parsing %s; error %v", v.degrees, err)
+		}
+		if math.Abs(result-v.expected) > 1e-9 {
+			t.Fatalf("Parsing %s expected: %v, received %v", v.degrees, v.expected, result)
+		}
+	}
+}
+
+var degreeStringErrors = []struct {
+	degrees string
+	offset  int
+	err     error
+}{
+	{"37:37:00:00", 9, ErrTooManyParts},
+	{"37x37", 2, ErrUnexpectedCharacter},
+	{"37:61", 3, ErrOutOfRange},
+	{"3761N", 2, ErrOutOfRange},
+	{"37.5:30", 2, ErrUnexpectedCharacter},
+	{"N37N", 3, ErrHemisphere},
+	{"-37N", 3, ErrHemisphere},
+	{"37N 30", 4, ErrUnexpectedCharacter},
+	{"N", 1, ErrMissingValue},
+	{"1e3", 1, ErrUnexpectedCharacter},
+	{"1.5N2", 3, ErrUnexpectedCharacter},
+	{"1222W", 0, ErrInvalidLongitude},
+	{"181", 0, ErrInvalidLongitude},
+	{"W 180:30", 2, ErrInvalidLongitude},
+	{"91N", 0, ErrInvalidLatitude},
+	{"90:00:01N", 0, ErrInvalidLatitude},
+}
+
+func TestDegreeStrToDecimalDegreeErrors(t *testing.T) {
+	for _, v := range degreeStringErrors {
+		_, err := DegreeStrToDecimalDegree(v.degrees)
+		var parseError *ParseError
+		if !errors.As(err, &parseError) {
+			t.Fatalf("Parsing %s expected a ParseError, received %v", v.degrees, err)
+		}
+		if parseError.Offset != v.offset || !errors.Is(err, v.err) {
+			t.Fatalf("Parsing %s expected: %v at %d, received %v", v.degrees, v.err, v.offset, err)
+		}
+	}
+}
+
+func TestNewCoordinateHemispheres(t *testing.T) {
+	coord, err := NewCoordinate("37°37'00\"N", "122°22'00\"W")
+	if err != nil {
+		t.Fatalf("Error parsing; error %v", err)
+	}
+	if !coord.Equal(coordKSFO.Coord) {
+		t.Fatalf("Expected: %v, received %v", coordKSFO.Coord, coord)
+	}
+
+	_, err = NewCoordinate("122°22'00\"W", "37°37'00\"N")
+	var parseError *ParseError
+	if !errors.As(err, &parseError) || parseError.Offset != 11 || !errors.Is(err, ErrHemisphere) {
+		t.Fatalf("Expected: %v at %d, received %v", ErrHemisphere, 11, err)
+	}
+}
+
+var coordinateStrings = []string{
+	"37°37'00\"N 122°22'00\"W",
+	"N37 37.0 W122 22.0",
+	"37-37-00N 122-22-00W",
+	"37.61667N 122.36667W",
+	"3737N12222W",
+	"373700N1222200W",
+	"W122 22.0 N37 37.0",
+	"37:37:00, 122:22:00",
+}
+
+func TestParseCoordinate(t *testing.T) {
+	for _, v := range coordinateStrings {
+		result, err := ParseCoordinate(v)
+		if err != nil {
+			t.Fatalf("Error parsing %s; error %v", v, err)
+		}
+		if Distance(result, coordKSFO.Coord) > 0.01 {
+			t.Fatalf("Parsing %s expected: %v, received %v", v, coordKSFO.Coord, result)
+		}
+	}
+
+	result, err := ParseCoordinate("3357S15110E")
+	if err != nil {
+		t.Fatalf("Error parsing; error %v", err)
+	}
+	expected := Coordinate{DegreesToRadians(-DegreeUnitsToDecimalDegree(33, 57, 0)), DegreesToRadians(-DegreeUnitsToDecimalDegree(151, 10, 0))}
+	if !result.Equal(expected) {
+		t.Fatalf("Expected: %v, received %v", expected, result)
+	}
+}
+
+func TestParseCoordinateErrors(t *testing.T) {
+	var coordinateErrors = []struct {
+		coordinate string
+		offset     int
+		err        error
+	}{
+		{"37.6167 122.3667", 0, ErrAmbiguous},
+		{"37.6167N 122.3667", 7, ErrHemisphere},
+		{"37N 38N", 6, ErrHemisphere},
+		{"3737N12x22W", 7, ErrUnexpectedCharacter},
+		{"95N 10W", 0, ErrInvalidLatitude},
+		{"91,0", 0, ErrInvalidLatitude},
+		{"10N 181E", 4, ErrInvalidLongitude},
+	}
+	for _, v := range coordinateErrors {
+		_, err := ParseCoordinate(v.coordinate)
+		var parseError *ParseError
+		if !errors.As(err, &parseError) || parseError.Offset != v.offset || !errors.Is(err, v.err) {
+			t.Fatalf("Parsing %s expected: %v at %d, received %v", v.coordinate, v.err, v.offset, err)
+		}
+	}
+}
+
+func TestParseCoordinateNormalised(t *testing.T) {
+	result, err := ParseCoordinate("90N 180E")
+	if err != nil {
+		t.Fatalf("Error parsing; error %v", err)
+	}
+	if result.Validate() != nil || result.Latitude != math.Pi/2 || result.Longitude != math.Pi {
+		t.Fatalf("Expected: %v, received %v", Coordinate{math.Pi / 2, math.Pi}, result)
+	}
+
+	var coord Coordinate
+	if err := coord.UnmarshalText([]byte("95N 10W")); !errors.Is(err, ErrInvalidLatitude) {
+		t.Fatalf("Expected: %v, received %v", ErrInvalidLatitude, err)
+	}
+}
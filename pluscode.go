@@ -0,0 +1,166 @@
+package greatcircle
+
+import (
+	"math"
+	"strings"
+)
+
+/*
+An Open Location Code, or Plus Code, names a cell by up to five pairs of
+base 20 digits, latitude then longitude, from 20 degrees down to 1/8000 of a
+degree, followed by up to five digits each dividing the cell into a grid of
+five rows by four columns. A "+" follows the eighth digit, and shorter codes are
+padded to it with zeros:
+
+	849V0000+       1 by 1 degrees
+	849VJJ8M+M8     about 14 by 14 metres
+*/
+
+const (
+	plusCodeAlphabet  = "23456789CFGHJMPQRVWX"
+	plusCodeSeparator = 8
+	plusCodePairs     = 10
+	// PlusCodeMaxPrecision is the most digits in a Plus Code, a cell a few centimetres across.
+	PlusCodeMaxPrecision = 15
+	// the cells of the longest code in a degree of latitude and of longitude
+	plusCodeLatitudeUnits  = 25000000
+	plusCodeLongitudeUnits = 8192000
+)
+
+/*
+ToPlusCode returns the Plus Code of the cell containing the Coordinate, of
+precision digits: an even number up to 10, then up to PlusCodeMaxPrecision.
+Odd precisions below 10 are rounded up. A precision of 10, "849VJJ8M+M8", is
+usual for a building.
+*/
+func (coord Coordinate) ToPlusCode(precision int) string {
+	if precision < 2 {
+		precision = 2
+	}
+	if precision > PlusCodeMaxPrecision {
+		precision = PlusCodeMaxPrecision
+	}
+	if precision < plusCodePairs && precision%2 == 1 {
+		precision++
+	}
+	latitude, longitude := coord.LatLonDegrees()
+	// rounded to a millionth of a cell first, so values such as 0.1 fall on the cell they name
+	latitudeValue := int64(math.Floor(math.Round((latitude+90)*plusCodeLatitudeUnits*1e6) / 1e6))
+	longitudeValue := int64(math.Floor(math.Round((longitude+180)*plusCodeLongitudeUnits*1e6) / 1e6))
+	if latitudeValue < 0 {
+		latitudeValue = 0
+	}
+	if latitudeValue >= 180*plusCodeLatitudeUnits {
+		// the north pole is in the northernmost cell
+		latitudeValue = 180*plusCodeLatitudeUnits - 1
+	}
+	longitudeValue = longitudeValue % (360 * plusCodeLongitudeUnits)
+	if longitudeValue < 0 {
+		longitudeValue = longitudeValue + 360*plusCodeLongitudeUnits
+	}
+
+	digits := make([]byte, PlusCodeMaxPrecision)
+	for i := PlusCodeMaxPrecision - 1; i >= plusCodePairs; i-- {
+		digits[i] = plusCodeAlphabet[latitudeValue%5*4+longitudeValue%4]
+		latitudeValue, longitudeValue = latitudeValue/5, longitudeValue/4
+	}
+	for i := plusCodePairs - 2; i >= 0; i = i - 2 {
+		digits[i] = plusCodeAlphabet[latitudeValue%20]
+		digits[i+1] = plusCodeAlphabet[longitudeValue%20]
+		latitudeValue, longitudeValue = latitudeValue/20, longitudeValue/20
+	}
+	code := string(digits[:precision])
+	if precision < plusCodeSeparator {
+		code = code + strings.Repeat("0", plusCodeSeparator-precision)
+	}
+	return code[:plusCodeSeparator] + "+" + code[plusCodeSeparator:]
+}
+
+/*
+DecodePlusCode returns the Cell a full Plus Code names. Lower case is accepted,
+and digits beyond PlusCodeMaxPrecision are ignored. Problems are reported as a
+*ParseError, wrapping ErrGridReference for a code that is not a full code:
+short codes such as "JJ8M+M8" are relative to a nearby place, and are not
+decoded.
+*/
+func DecodePlusCode(code string) (Cell, error) {
+	text := strings.ToUpper(strings.TrimSpace(code))
+	separator := strings.IndexByte(text, '+')
+	if separator < 0 {
+		return Cell{}, &ParseError{text, len(text), ErrMissingValue}
+	}
+	if separator != plusCodeSeparator {
+		return Cell{}, &ParseError{text, separator, ErrGridReference}
+	}
+	digits := text[:separator] + text[separator+1:]
+	// offset returns the offset in text of digits[i]
+	offset := func(i int) int {
+		if i < separator {
+			return i
+		}
+		return i + 1
+	}
+	if padding := strings.IndexByte(digits, '0'); padding >= 0 {
+		if padding == 0 || padding%2 == 1 || padding > separator ||
+			strings.Trim(digits[padding:separator], "0") != "" || len(digits) > separator {
+			return Cell{}, &ParseError{text, offset(padding), ErrGridReference}
+		}
+		digits = digits[:padding]
+	}
+	if len(digits) == plusCodeSeparator+1 {
+		// a single digit after the separator
+		return Cell{}, &ParseError{text, len(text), ErrMissingValue}
+	}
+	for i := 0; i < len(digits); i++ {
+		if strings.IndexByte(plusCodeAlphabet, digits[i]) < 0 {
+			return Cell{}, &ParseError{text, offset(i), ErrUnexpectedCharacter}
+		}
+	}
+	// the first pair must be within 90 degrees of latitude and 180 of longitude
+	if strings.IndexByte(plusCodeAlphabet, digits[0]) >= 9 {
+		return Cell{}, &ParseError{text, 0, ErrGridReference}
+	}
+	if strings.IndexByte(plusCodeAlphabet, digits[1]) >= 18 {
+		return Cell{}, &ParseError{text, 1, ErrGridReference}
+	}
+	if len(digits) > PlusCodeMaxPrecision {
+		digits = digits[:PlusCodeMaxPrecision]
+	}
+
+	var latitudeValue, longitudeValue int64
+	latitudePlace := int64(20 * 20 * plusCodeLatitudeUnits)
+	longitudePlace := int64(20 * 20 * plusCodeLongitudeUnits)
+	for i := 0; i < len(digits); i++ {
+		value := int64(strings.IndexByte(plusCodeAlphabet, digits[i]))
+		switch {
+		case i >= plusCodePairs:
+			latitudePlace, longitudePlace = latitudePlace/5, longitudePlace/4
+			latitudeValue = latitudeValue + value/4*latitudePlace
+			longitudeValue = longitudeValue + value%4*longitudePlace
+		case i%2 == 0:
+			latitudePlace = latitudePlace / 20
+			latitudeValue = latitudeValue + value*latitudePlace
+		default:
+			longitudePlace = longitudePlace / 20
+			longitudeValue = longitudeValue + value*longitudePlace
+		}
+	}
+	return newCell(
+		float64(latitudeValue)/plusCodeLatitudeUnits-90,
+		float64(longitudeValue)/plusCodeLongitudeUnits-180,
+		float64(latitudeValue+latitudePlace)/plusCodeLatitudeUnits-90,
+		float64(longitudeValue+longitudePlace)/plusCodeLongitudeUnits-180,
+	), nil
+}
+
+/*
+NewNamedCoordinateFromPlusCode creates a NamedCoordinate at the centre of the
+cell a full Plus Code names, as DecodePlusCode does.
+*/
+func NewNamedCoordinateFromPlusCode(name, code string) (NamedCoordinate, error) {
+	cell, err := DecodePlusCode(code)
+	if err != nil {
+		return NamedCoordinate{}, err
+	}
+	return NamedCoordinate{cell.Centre, name}, nil
+}
@@ -0,0 +1,113 @@
+package greatcircle
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestToPlusCode(t *testing.T) {
+	// from the Open Location Code test data
+	cases := []struct {
+		latitude, longitude float64
+		precision           int
+		expected            string
+	}{
+		{20.375, 2.775, 6, "7FG49Q00+"},
+		{20.3700625, 2.7821875, 10, "7FG49QCJ+2V"},
+		{20.3701125, 2.782234375, 11, "7FG49QCJ+2VX"},
+		{20.3701135, 2.78223535156, 13, "7FG49QCJ+2VXGJ"},
+		{47.0000625, 8.0000625, 10, "8FVC2222+22"},
+		{-41.2730625, 174.7859375, 10, "4VCPPQGP+Q9"},
+		{0.5, -179.5, 4, "62G20000+"},
+		{-89.5, -179.5, 4, "22220000+"},
+		{0.5, 179.5, 4, "6VGX0000+"},
+		{1, 1, 11, "6FH32222+222"},
+		{90, 1, 4, "CFX30000+"},
+		{90, 1, 10, "CFX3X2X2+X2"},
+		{1, 180, 4, "62H20000+"},
+		{37 + 37.0/60, -122 - 22.0/60, 10, "849VJJ8M+M8"},
+		{20.375, 2.775, 5, "7FG49Q00+"},
+	}
+	for _, c := range cases {
+		coord, _ := FromLatLonDegrees(c.latitude, c.longitude)
+		result := coord.ToPlusCode(c.precision)
+		if result != c.expected {
+			t.Fatalf("Expected: %s, received %s", c.expected, result)
+		}
+	}
+}
+
+func TestDecodePlusCode(t *testing.T) {
+	for _, code := range []string{"7FG49QCJ+2V", "7fg49qcj+2v"} {
+		cell, err := DecodePlusCode(code)
+		if err != nil {
+			t.Fatalf("Error decoding %q; error %v", code, err)
+		}
+		south, west := cell.SouthWest.LatLonDegrees()
+		north, east := cell.NorthEast.LatLonDegrees()
+		if math.Abs(south-20.37) > 1e-9 || math.Abs(west-2.782125) > 1e-9 ||
+			math.Abs(north-20.370125) > 1e-9 || math.Abs(east-2.78225) > 1e-9 {
+			t.Fatalf("Expected: 20.37,2.782125 to 20.370125,2.78225, received %v,%v to %v,%v", south, west, north, east)
+		}
+		latitude, longitude := cell.Centre.LatLonDegrees()
+		if math.Abs(latitude-20.3700625) > 1e-9 || math.Abs(longitude-2.7821875) > 1e-9 {
+			t.Fatalf("Expected: 20.3700625,2.7821875, received %v,%v", latitude, longitude)
+		}
+	}
+
+	cell, _ := DecodePlusCode("7FG40000+")
+	south, west := cell.SouthWest.LatLonDegrees()
+	north, east := cell.NorthEast.LatLonDegrees()
+	if math.Abs(south-20) > 1e-9 || math.Abs(west-2) > 1e-9 || math.Abs(north-21) > 1e-9 || math.Abs(east-3) > 1e-9 {
+		t.Fatalf("Expected: 20,2 to 21,3, received %v,%v to %v,%v", south, west, north, east)
+	}
+
+	errorCases := []struct {
+		code     string
+		expected error
+	}{
+		{"", ErrMissingValue},
+		{"7FG49QCJ2V", ErrMissingValue},
+		{"7FG49QCJ+2", ErrMissingValue},
+		{"9QCJ+2V", ErrGridReference},
+		{"7FG400+", ErrGridReference},
+		{"7FG40000+2V", ErrGridReference},
+		{"7F0G0000+", ErrGridReference},
+		{"7FG4900+", ErrGridReference},
+		{"7FG49QCJ+2A", ErrUnexpectedCharacter},
+		{"F2G49QCJ+2V", ErrGridReference},
+		{"7WG49QCJ+2V", ErrGridReference},
+	}
+	for _, c := range errorCases {
+		if _, err := DecodePlusCode(c.code); !errors.Is(err, c.expected) {
+			t.Fatalf("Expected: %v, received %v from %q", c.expected, err, c.code)
+		}
+	}
+
+	random := rand.New(rand.NewSource(21))
+	for i := 0; i < 1000; i++ {
+		coord, _ := FromLatLonDegrees(random.Float64()*180-90, random.Float64()*360-180)
+		for _, precision := range []int{2, 4, 6, 8, 10, 11, 12, 13, 14, 15} {
+			code := coord.ToPlusCode(precision)
+			cell, err := DecodePlusCode(code)
+			if err != nil || !cell.Contains(coord) {
+				t.Fatalf("Expected %v from %s to contain %v, %v", cell, code, coord, err)
+			}
+		}
+	}
+}
+
+func TestNewNamedCoordinateFromPlusCode(t *testing.T) {
+	result, err := NewNamedCoordinateFromPlusCode("KSFO", "849VJJ8M+M8")
+	if err != nil {
+		t.Fatalf("Error creating; error %v", err)
+	}
+	if result.Name != "KSFO" || Distance(result.Coord, coordKSFO.Coord)*metresPerNM > 10 {
+		t.Fatalf("Expected: %v, received %v", coordKSFO, result)
+	}
+	if _, err := NewNamedCoordinateFromPlusCode("KSFO", "JJ8M+M8"); !errors.Is(err, ErrGridReference) {
+		t.Fatalf("Expected: %v, received %v", ErrGridReference, err)
+	}
+}
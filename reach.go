@@ -0,0 +1,105 @@
+package greatcircle
+
+import (
+	"sort"
+)
+
+/*
+ReachResult describes a point of interest within reach of a route.
+*/
+type ReachResult struct {
+	Coord Coordinate
+	// Index is the position of Coord in the list of points of interest searched
+	Index int
+	// CrossTrack is the distance off course in nautical miles;
+	// positive is right of course, negative left
+	CrossTrack float64
+	// AlongTrack is the distance in nautical miles from the start of the route
+	// to the point abeam Coord; negative when that point is behind the start
+	AlongTrack float64
+	// Nearest is the point on the route nearest to Coord; the start or end of
+	// the route when Coord is behind or beyond it
+	Nearest Coordinate
+	// Distance is the distance in nautical miles from Nearest to Coord
+	Distance float64
+}
+
+/*
+ReachResults is a list of points of interest within reach of a route.
+*/
+type ReachResults []ReachResult
+
+/*
+SortByAlongTrack orders the results by their position along the route, the order
+in which they are met. Results abeam the same point are ordered by Index.
+*/
+func (results ReachResults) SortByAlongTrack() {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].AlongTrack != results[j].AlongTrack {
+			return results[i].AlongTrack < results[j].AlongTrack
+		}
+		return results[i].Index < results[j].Index
+	})
+}
+
+/*
+SortByDistance orders the results by their distance from the route, nearest
+first. Results the same distance from the route are ordered by Index.
+*/
+func (results ReachResults) SortByDistance() {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Distance != results[j].Distance {
+			return results[i].Distance < results[j].Distance
+		}
+		return results[i].Index < results[j].Index
+	})
+}
+
+/*
+Coordinates returns the Coord of each result, in order.
+*/
+func (results ReachResults) Coordinates() []Coordinate {
+	var coords []Coordinate
+	for _, result := range results {
+		coords = append(coords, result.Coord)
+	}
+	return coords
+}
+
+/*
+FindPointsInReach returns every Coordinate of coords within distance nautical
+miles of the (routeStartCoord, routeEndCoord) route, as PointsInReach does, with
+where each lies relative to the route.
+
+Results are sorted by along track distance; use SortByDistance to order them
+by distance from the route instead. Every match is kept, including repeated
+Coordinates and Coordinates the same distance from the route.
+*/
+func FindPointsInReach(routeStartCoord, routeEndCoord Coordinate, distance float64, coords []Coordinate) ReachResults {
+	results := ReachResults{}
+	for i, coord := range coords {
+		if result, ok := reachResult(routeStartCoord, routeEndCoord, distance, coord); ok {
+			result.Index = i
+			results = append(results, result)
+		}
+	}
+	results.SortByAlongTrack()
+	return results
+}
+
+// reachResult describes coord relative to the route, if it is within distance nautical miles
+func reachResult(routeStartCoord, routeEndCoord Coordinate, distance float64, coord Coordinate) (ReachResult, bool) {
+	// the nearest point is abeam coord, but no further back than the start or on than the end
+	nearest := segmentClosestPoint(routeStartCoord, routeEndCoord, coord)
+	distanceBetweenPoints := Distance(nearest, coord)
+	if distanceBetweenPoints > distance {
+		return ReachResult{}, false
+	}
+	return ReachResult{
+		Coord:      coord,
+		CrossTrack: RadiansToNM(CrossTrackError(routeStartCoord, routeEndCoord, coord)),
+		AlongTrack: RadiansToNM(alongTrack(routeStartCoord, routeEndCoord, coord)),
+		Nearest:    nearest,
+		Distance:   distanceBetweenPoints,
+	}, true
+}
@@ -0,0 +1,136 @@
+package greatcircle
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestFindPointsInReach(t *testing.T) {
+	// heading south east from KSFO to KLAX, the inland airports are left of course
+	coordWest := Coordinate{DegreesToRadians(36.5), DegreesToRadians(122.5)}
+	pois := []Coordinate{coordKMOD.Coord, coordKJFK.Coord, coordWest, coordKSJC.Coord, coordKMAE.Coord}
+	results := FindPointsInReach(coordKSFO.Coord, coordKLAX.Coord, 100, pois)
+
+	expected := []int{3, 0, 2, 4}
+	if len(results) != len(expected) {
+		t.Fatalf("Expected %v results, received %v", len(expected), results)
+	}
+	for i, result := range results {
+		if result.Index != expected[i] || result.Coord != pois[expected[i]] {
+			t.Fatalf("Expected: %v, received %v", pois[expected[i]], result)
+		}
+		if i > 0 && result.AlongTrack < results[i-1].AlongTrack {
+			t.Fatalf("Expected results in along track order, received %v", results)
+		}
+		if math.Abs(result.Distance-Distance(result.Nearest, result.Coord)) > 1e-9 ||
+			math.Abs(math.Abs(result.CrossTrack)-result.Distance) > 0.01 {
+			t.Fatalf("Expected the cross track error to be the distance from the route, received %v", result)
+		}
+	}
+	if results[1].CrossTrack >= 0 || results[2].CrossTrack <= 0 {
+		t.Fatalf("Expected KMOD left of course and %v right, received %v and %v", coordWest, results[1].CrossTrack, results[2].CrossTrack)
+	}
+
+	results.SortByDistance()
+	for i := 1; i < len(results); i++ {
+		if results[i].Distance < results[i-1].Distance {
+			t.Fatalf("Expected results in distance order, received %v", results)
+		}
+	}
+	if !reflect.DeepEqual(results.Coordinates(), PointsInReach(coordKSFO.Coord, coordKLAX.Coord, 100, pois)) {
+		t.Fatalf("Expected: %v, received %v", results.Coordinates(), PointsInReach(coordKSFO.Coord, coordKLAX.Coord, 100, pois))
+	}
+}
+
+func TestFindPointsInReachEnds(t *testing.T) {
+	// north of KSFO is behind the start of the route, and south east of KLAX beyond its end
+	behind, _ := FromLatLonDegrees(38.5, -122.8)
+	beyond, _ := FromLatLonDegrees(33.2, -117.6)
+	length := Distance(coordKSFO.Coord, coordKLAX.Coord)
+	results := FindPointsInReach(coordKSFO.Coord, coordKLAX.Coord, 150, []Coordinate{beyond, behind})
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, received %v", results)
+	}
+	cases := []struct {
+		result  ReachResult
+		coord   Coordinate
+		nearest Coordinate
+	}{
+		{results[0], behind, coordKSFO.Coord},
+		{results[1], beyond, coordKLAX.Coord},
+	}
+	for _, c := range cases {
+		if c.result.Coord != c.coord || Distance(c.result.Nearest, c.nearest) > 1e-3 {
+			t.Fatalf("Expected: %v nearest %v, received %v", c.coord, c.nearest, c.result)
+		}
+		if math.Abs(c.result.Distance-Distance(c.nearest, c.coord)) > 1e-3 {
+			t.Fatalf("Expected: %v, received %v", Distance(c.nearest, c.coord), c.result.Distance)
+		}
+	}
+	if results[0].AlongTrack >= 0 || results[1].AlongTrack <= length {
+		t.Fatalf("Expected along track before 0 and beyond %v, received %v and %v", length, results[0].AlongTrack, results[1].AlongTrack)
+	}
+
+	// on course but beyond the end is as far as it is from the end, not on the route
+	onCourse := coordKLAX.Coord.Destination(FinalBearing(coordKSFO.Coord, coordKLAX.Coord), 150)
+	if results := FindPointsInReach(coordKSFO.Coord, coordKLAX.Coord, 100, []Coordinate{onCourse}); len(results) != 0 {
+		t.Fatalf("Expected no results, received %v", results)
+	}
+}
+
+func TestMultiPointRoutePOISNearestEnd(t *testing.T) {
+	start, _ := FromLatLonDegrees(0, 0)
+	end, _ := FromLatLonDegrees(0, 10)
+	beyond, _ := FromLatLonDegrees(0.1, 20)
+	past, _ := FromLatLonDegrees(0.1, 10.05)
+	pois := []Coordinate{beyond, past}
+	for _, results := range [][]MultiPoint{
+		MultiPointRoutePOIS([]Coordinate{start, end}, pois, 10),
+		NewIndex(pois).MultiPointRoutePOIS([]Coordinate{start, end}, 10),
+	} {
+		if len(results) != 1 || results[0].Poi != past || !results[0].Neareast.Equal(end) {
+			t.Fatalf("Expected %v nearest the end of the route, received %v", past, results)
+		}
+		if math.Abs(results[0].Distance-Distance(end, past)) > 1e-6 {
+			t.Fatalf("Expected: %v, received %v", Distance(end, past), results[0].Distance)
+		}
+	}
+}
+
+func TestPointsInReachDuplicates(t *testing.T) {
+	// points the same distance from the route are all kept, in their original order
+	pois := []Coordinate{coordKSJC.Coord, coordKMAE.Coord, coordKSJC.Coord, coordKMAE.Coord}
+	result := PointsInReach(coordKSFO.Coord, coordKLAX.Coord, 100, pois)
+	expected := []Coordinate{coordKSJC.Coord, coordKSJC.Coord, coordKMAE.Coord, coordKMAE.Coord}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Expected: %v, received %v", expected, result)
+	}
+	results := FindPointsInReach(coordKSFO.Coord, coordKLAX.Coord, 100, pois)
+	if len(results) != 4 || results[0].Index != 0 || results[1].Index != 2 {
+		t.Fatalf("Expected the repeated KSJC in its original order, received %v", results)
+	}
+	if result := PointsInReach(coordKSFO.Coord, coordKLAX.Coord, 1, pois); result != nil {
+		t.Fatalf("Expected no points, received %v", result)
+	}
+}
+
+func TestIndexFindPointsInReach(t *testing.T) {
+	random := rand.New(rand.NewSource(6))
+	pois := randomCoordinates(random, 3000)
+	pois = append(pois, pois[:100]...)
+	index := NewIndex(pois)
+	for i := 0; i < 20; i++ {
+		route := randomRoute(random, 1)
+		expected := FindPointsInReach(route[0], route[1], 150, pois)
+		result := index.FindPointsInReach(route[0], route[1], 150)
+		if !reflect.DeepEqual(result, expected) {
+			t.Fatalf("Expected: %v, received %v", expected, result)
+		}
+	}
+	expected := FindPointsInReach(coordKSFO.Coord, coordKSFO.Coord, 500, pois)
+	if result := index.FindPointsInReach(coordKSFO.Coord, coordKSFO.Coord, 500); !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Expected: %v, received %v", expected, result)
+	}
+}
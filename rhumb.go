@@ -0,0 +1,165 @@
+package greatcircle
+
+import (
+	"math"
+)
+
+/*
+A rhumb line (or loxodrome) is a path of constant true course. It crosses
+every meridian at the same angle, so it is the track flown or sailed on a
+constant heading; it is longer than the great circle between the same two
+points except when travelling along a meridian or the equator.
+
+Formulae adapted from http://williams.best.vwh.net/avform.htm#Rhumb
+*/
+
+/*
+RhumbDistance calculates the distance between two Coordinates along the
+rhumb line that joins them.
+
+Result is in nautical miles.
+*/
+func RhumbDistance(point1, point2 Coordinate) float64 {
+	_, distance := rhumbCourse(point1, point2)
+	return distance
+}
+
+/*
+RhumbBearing provides the constant true course of the rhumb line
+from point1 to point2.
+
+Result is in radians.
+*/
+func RhumbBearing(point1, point2 Coordinate) float64 {
+	bearing, _ := rhumbCourse(point1, point2)
+	return bearing
+}
+
+/*
+RhumbDestination determines the Coordinate reached by travelling distance
+nautical miles from coord on the constant true course bearing (radians).
+
+A rhumb line spirals towards a pole without ever crossing it; if distance
+would take the rhumb line beyond a pole then the pole is returned.
+*/
+func RhumbDestination(coord Coordinate, bearing, distance float64) Coordinate {
+	d := NMToRadians(distance)
+	latitude := coord.Latitude + d*math.Cos(bearing)
+	if math.Abs(latitude) >= math.Pi/2 {
+		return Coordinate{math.Copysign(math.Pi/2, latitude), coord.Longitude}
+	}
+	q := rhumbStretch(coord.Latitude, latitude)
+	dlon := -d * math.Sin(bearing) / q
+	return Coordinate{latitude, math.Remainder(coord.Longitude+dlon, 2*math.Pi)}
+}
+
+/*
+RhumbMidpoint determines the Coordinate half way along the rhumb line
+between point1 and point2.
+*/
+func RhumbMidpoint(point1, point2 Coordinate) Coordinate {
+	bearing, distance := rhumbCourse(point1, point2)
+	return RhumbDestination(point1, bearing, distance/2)
+}
+
+/*
+RhumbIntermediatePoint determines the Coordinate that is fraction of the way
+along the rhumb line from point1 to point2; 0 is point1 and 1 point2.
+*/
+func RhumbIntermediatePoint(point1, point2 Coordinate, fraction float64) Coordinate {
+	bearing, distance := rhumbCourse(point1, point2)
+	return RhumbDestination(point1, bearing, distance*fraction)
+}
+
+/*
+RhumbIntermediatePoints returns n+1 Coordinates dividing the rhumb line from
+point1 to point2 into n equal parts, including both point1 and point2.
+*/
+func RhumbIntermediatePoints(point1, point2 Coordinate, n int) []Coordinate {
+	return intermediatePoints(point1, point2, n, RhumbIntermediatePoint)
+}
+
+/*
+RhumbDensify returns Coordinates along the rhumb line from point1 to point2,
+including both, no more than maxDistance nautical miles apart.
+*/
+func RhumbDensify(point1, point2 Coordinate, maxDistance float64) []Coordinate {
+	return RhumbIntermediatePoints(point1, point2, densifyParts(RhumbDistance(point1, point2), maxDistance))
+}
+
+/*
+RhumbClosestPoint determines the Coordinate on the rhumb line between
+routeStartCoord and routeEndCoord that is closest to actualCoord.
+
+Unlike ClosestPoint the result is limited to the leg itself, as a rhumb line
+continued beyond its end points spirals into the poles.
+*/
+func RhumbClosestPoint(routeStartCoord, routeEndCoord, actualCoord Coordinate) Coordinate {
+	bearing, distance := rhumbCourse(routeStartCoord, routeEndCoord)
+	along := func(fraction float64) Coordinate {
+		return RhumbDestination(routeStartCoord, bearing, distance*fraction)
+	}
+	// golden section search for the fraction along the leg with the
+	// smallest great circle distance to actualCoord
+	ratio := (math.Sqrt(5) - 1) / 2
+	low, high := 0.0, 1.0
+	for high-low > 1e-9 {
+		mid1 := high - ratio*(high-low)
+		mid2 := low + ratio*(high-low)
+		if Distance(along(mid1), actualCoord) <= Distance(along(mid2), actualCoord) {
+			high = mid2
+		} else {
+			low = mid1
+		}
+	}
+	return along((low + high) / 2)
+}
+
+/*
+rhumbCourse is the constant true course (radians) and distance (nautical miles)
+of the rhumb line from point1 to point2, taking the shorter way around the Earth.
+*/
+func rhumbCourse(point1, point2 Coordinate) (float64, float64) {
+	dlat := point2.Latitude - point1.Latitude
+	dlonW := math.Mod(point2.Longitude-point1.Longitude, 2*math.Pi)
+	if dlonW < 0 {
+		dlonW += 2 * math.Pi
+	}
+	dlonE := 2*math.Pi - dlonW
+	if dlonE == 2*math.Pi {
+		dlonE = 0
+	}
+	dphi := rhumbStretchedLatitude(point2.Latitude) - rhumbStretchedLatitude(point1.Latitude)
+	q := rhumbStretch(point1.Latitude, point2.Latitude)
+
+	var bearing, dlon float64
+	if dlonW < dlonE {
+		bearing = math.Atan2(-dlonW, dphi)
+		dlon = dlonW
+	} else {
+		bearing = math.Atan2(dlonE, dphi)
+		dlon = dlonE
+	}
+	if dlat == 0 && dlon == 0 {
+		return 2 * math.Pi, 0
+	}
+	distance := math.Sqrt(q*q*dlon*dlon + dlat*dlat)
+	return normalizeBearing(bearing), RadiansToNM(distance)
+}
+
+// rhumbStretchedLatitude is the Mercator projection of a latitude
+func rhumbStretchedLatitude(latitude float64) float64 {
+	return math.Log(math.Tan(latitude/2 + math.Pi/4))
+}
+
+/*
+rhumbStretch is the ratio of latitude change to stretched latitude change
+between two latitudes; along a parallel it is the cosine of the latitude.
+*/
+func rhumbStretch(latitude1, latitude2 float64) float64 {
+	dlat := latitude2 - latitude1
+	if math.Abs(dlat) < 1e-12 {
+		return math.Cos(latitude1)
+	}
+	return dlat / (rhumbStretchedLatitude(latitude2) - rhumbStretchedLatitude(latitude1))
+}
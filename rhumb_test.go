@@ -0,0 +1,210 @@
+package greatcircle
+
+import (
+	"math"
+	"testing"
+)
+
+var coordDover = Coordinate{DegreesToRadians(DegreeUnitsToDecimalDegree(51, 7, 32)), -DegreesToRadians(DegreeUnitsToDecimalDegree(1, 20, 17))}
+var coordCalais = Coordinate{DegreesToRadians(DegreeUnitsToDecimalDegree(50, 57, 48)), -DegreesToRadians(DegreeUnitsToDecimalDegree(1, 51, 9))}
+
+var rhumbCourses = []struct {
+	point1           Coordinate
+	point2           Coordinate
+	expectedBearing  float64
+	expectedDistance float64
+}{
+	// from the Aviation Formulary
+	{coordKLAX.Coord, coordKJFK.Coord, DegreesToRadians(79.32), 2164.6},
+	{coordKJFK.Coord, coordKLAX.Coord, DegreesToRadians(259.32), 2164.6},
+	// due west along the equator, across the antimeridian
+	{Coordinate{0, DegreesToRadians(179)}, Coordinate{0, DegreesToRadians(-179)}, DegreesToRadians(270), 120},
+	// due south along a meridian
+	{coordKSFO.Coord, Coordinate{0, coordKSFO.Coord.Longitude}, math.Pi, RadiansToNM(coordKSFO.Coord.Latitude)},
+}
+
+func TestRhumbBearing(t *testing.T) {
+	for _, v := range rhumbCourses {
+		result := RhumbBearing(v.point1, v.point2)
+		if math.Abs(result-v.expectedBearing) > DegreesToRadians(0.01) {
+			t.Fatalf("Rhumb bearing of %v %v expected: %v, received %v", v.point1, v.point2,
+				RadiansToDegrees(v.expectedBearing), RadiansToDegrees(result))
+		}
+	}
+	// published by Chris Veness: 116° 38′ 10″
+	result := RhumbBearing(coordDover, coordCalais)
+	if math.Abs(result-DegreesToRadians(DegreeUnitsToDecimalDegree(116, 38, 10))) > DegreesToRadians(1.0/3600) {
+		t.Fatalf("Expected: %v, received %v", DegreeUnitsToDecimalDegree(116, 38, 10), RadiansToDegrees(result))
+	}
+}
+
+func TestRhumbDistance(t *testing.T) {
+	for _, v := range rhumbCourses {
+		result := RhumbDistance(v.point1, v.point2)
+		if math.Abs(result-v.expectedDistance) > 0.1 {
+			t.Fatalf("Rhumb distance of %v %v expected: %v, received %v", v.point1, v.point2, v.expectedDistance, result)
+		}
+	}
+	if RhumbDistance(coordKLAX.Coord, coordKJFK.Coord) <= Distance(coordKLAX.Coord, coordKJFK.Coord) {
+		t.Fatalf("Expected the rhumb line to be longer than the great circle")
+	}
+}
+
+func TestRhumbDestination(t *testing.T) {
+	for _, v := range rhumbCourses {
+		result := RhumbDestination(v.point1, v.expectedBearing, v.expectedDistance)
+		if Distance(result, v.point2) > 0.5 {
+			t.Fatalf("Expected: %v, received %v", v.point2, result)
+		}
+	}
+	// spiralling into the pole
+	result := RhumbDestination(coordKSFO.Coord, DegreesToRadians(45), 10000)
+	if result.Latitude != math.Pi/2 {
+		t.Fatalf("Expected: %v, received %v", math.Pi/2, result.Latitude)
+	}
+}
+
+func TestRhumbMidpoint(t *testing.T) {
+	// published by Chris Veness: 51° 02′ 40″ N, 001° 35′ 43″ E
+	expected := Coordinate{DegreesToRadians(DegreeUnitsToDecimalDegree(51, 2, 40)), -DegreesToRadians(DegreeUnitsToDecimalDegree(1, 35, 43))}
+	result := RhumbMidpoint(coordDover, coordCalais)
+	if Distance(result, expected) > 0.05 {
+		t.Fatalf("Expected: %v, received %v", expected, result)
+	}
+}
+
+func TestRhumbClosestPoint(t *testing.T) {
+	// along the equator the closest point is on the same meridian
+	result := RhumbClosestPoint(Coordinate{0, 0.1}, Coordinate{0, -0.1}, Coordinate{0.01, 0.02})
+	if !result.Equal(Coordinate{0, 0.02}) {
+		t.Fatalf("Expected: %v, received %v", Coordinate{0, 0.02}, result)
+	}
+	// beyond the end of the leg the end point is closest
+	result = RhumbClosestPoint(Coordinate{0, 0.1}, Coordinate{0, -0.1}, Coordinate{0.01, -0.2})
+	if !result.Equal(Coordinate{0, -0.1}) {
+		t.Fatalf("Expected: %v, received %v", Coordinate{0, -0.1}, result)
+	}
+}
+
+func TestMultiPointRouteLegTypes(t *testing.T) {
+	route := NewMultiPointRoute([]NamedCoordinate{coordKSFO, coordKLAX, coordKJFK})
+	greatCircle := route.Distance()
+	expected := Distance(coordKSFO.Coord, coordKLAX.Coord) + Distance(coordKLAX.Coord, coordKJFK.Coord)
+	if math.Abs(greatCircle-expected) > 0.0001 {
+		t.Fatalf("Expected: %v, received %v", expected, greatCircle)
+	}
+
+	legTyped := route.WithLegTypes()
+	legTyped.SetLegType(1, RhumbLineLeg)
+	if legTyped.LegType(0) != GreatCircleLeg || legTyped.LegType(1) != RhumbLineLeg {
+		t.Fatalf("Expected leg types %v %v, received %v %v", GreatCircleLeg, RhumbLineLeg, legTyped.LegType(0), legTyped.LegType(1))
+	}
+	expected = Distance(coordKSFO.Coord, coordKLAX.Coord) + RhumbDistance(coordKLAX.Coord, coordKJFK.Coord)
+	if math.Abs(legTyped.Distance()-expected) > 0.0001 {
+		t.Fatalf("Expected: %v, received %v", expected, legTyped.Distance())
+	}
+	if math.Abs(route.Distance()-greatCircle) > 0.0001 || len(route) != 3 {
+		t.Fatalf("Expected the great circle route unchanged, received %v", route)
+	}
+
+	// the leg types belong to the LegTypedRoute, not its points
+	if coordKJFK != (NamedCoordinate{coordKJFK.Coord, "KJFK"}) {
+		t.Fatalf("Expected: KJFK unchanged, received %v", coordKJFK)
+	}
+	literal := LegTypedRoute{Points: []NamedCoordinate{coordKSFO, coordKLAX}}
+	if literal.LegType(0) != GreatCircleLeg || literal.LegType(5) != GreatCircleLeg {
+		t.Fatalf("Expected great circle legs, received %v %v", literal.LegType(0), literal.LegType(5))
+	}
+	literal.SetLegType(0, RhumbLineLeg)
+	if literal.LegType(0) != RhumbLineLeg {
+		t.Fatalf("Expected: %v, received %v", RhumbLineLeg, literal.LegType(0))
+	}
+}
+
+func TestMultiPointRouteRhumbPOIS(t *testing.T) {
+	// the midpoint of the rhumb line is well south of the great circle
+	poi := RhumbMidpoint(coordKLAX.Coord, coordKJFK.Coord)
+	route := NewMultiPointRoute([]NamedCoordinate{coordKLAX, coordKJFK})
+	if len(route.POIS([]Coordinate{poi}, 10)) != 0 {
+		t.Fatalf("Expected %v to be out of reach of the great circle", poi)
+	}
+	results := route.WithLegTypes(RhumbLineLeg).POIS([]Coordinate{poi}, 10)
+	if len(results) != 1 || results[0].Distance > 0.01 {
+		t.Fatalf("Expected %v to be on the rhumb line, received %v", poi, results)
+	}
+}
+
+func TestMultiPointRoutePOISEnds(t *testing.T) {
+	start, _ := FromLatLonDegrees(0, 0)
+	end, _ := FromLatLonDegrees(0, 10)
+	beyond, _ := FromLatLonDegrees(0.1, 20)
+	past, _ := FromLatLonDegrees(0.1, 10.05)
+	route := NewMultiPointRoute([]NamedCoordinate{start.ToNamedCoordinate(), end.ToNamedCoordinate()})
+	for _, legType := range []LegType{GreatCircleLeg, RhumbLineLeg} {
+		// 600 NM beyond the end of the leg, though 6 NM from its continuation
+		if results := route.WithLegTypes(legType).POIS([]Coordinate{beyond}, 10); len(results) != 0 {
+			t.Fatalf("Expected nothing in reach of the %v leg, received %v", legType, results)
+		}
+		results := route.WithLegTypes(legType).POIS([]Coordinate{past}, 10)
+		if len(results) != 1 || !results[0].Neareast.Equal(end) || math.Abs(results[0].Distance-Distance(end, past)) > 1e-6 {
+			t.Fatalf("Expected %v measured from the end of the %v leg, received %v", past, legType, results)
+		}
+	}
+}
+
+func TestRhumbIntermediatePoint(t *testing.T) {
+	result := RhumbIntermediatePoint(coordDover, coordCalais, 0.5)
+	if !result.Equal(RhumbMidpoint(coordDover, coordCalais)) {
+		t.Fatalf("Expected: %v, received %v", RhumbMidpoint(coordDover, coordCalais), result)
+	}
+	points := RhumbDensify(coordKLAX.Coord, coordKJFK.Coord, 100)
+	if len(points) != 23 || !points[22].Equal(coordKJFK.Coord) {
+		t.Fatalf("Expected 23 points from KLAX to KJFK, received %v", points)
+	}
+	for i := 1; i < len(points); i++ {
+		if math.Abs(RhumbBearing(points[i-1], points[i])-DegreesToRadians(79.32)) > 0.0001 {
+			t.Fatalf("Expected: %v, received %v", DegreesToRadians(79.32), RhumbBearing(points[i-1], points[i]))
+		}
+	}
+}
+
+func TestMultiPointRouteIntermediatePoints(t *testing.T) {
+	route := NewMultiPointRoute([]NamedCoordinate{coordKSFO, coordKLAX, coordKJFK}).WithLegTypes(GreatCircleLeg, RhumbLineLeg)
+	if result := route.LegIntermediatePoint(0, 0.37); !result.Equal(IntermediatePoint(coordKSFO.Coord, coordKLAX.Coord, 0.37)) {
+		t.Fatalf("Expected a point on the great circle, received %v", result)
+	}
+	if result := route.LegIntermediatePoint(1, 0.5); !result.Equal(RhumbMidpoint(coordKLAX.Coord, coordKJFK.Coord)) {
+		t.Fatalf("Expected a point on the rhumb line, received %v", result)
+	}
+	if result := route.LegIntermediatePoints(1, 3); len(result) != 4 || !result[1].Equal(RhumbIntermediatePoint(coordKLAX.Coord, coordKJFK.Coord, 1.0/3)) {
+		t.Fatalf("Expected points on the rhumb line, received %v", result)
+	}
+	if result := route.DensifyLeg(0, 50); len(result) != len(Densify(coordKSFO.Coord, coordKLAX.Coord, 50)) {
+		t.Fatalf("Expected: %v, received %v", Densify(coordKSFO.Coord, coordKLAX.Coord, 50), result)
+	}
+
+	var pointsAtDistance = []struct {
+		distance float64
+		expected Coordinate
+		leg      int
+	}{
+		{-10, coordKSFO.Coord, 0},
+		{100, IntermediatePoint(coordKSFO.Coord, coordKLAX.Coord, 100/route.legDistance(0)), 0},
+		{route.legDistance(0), coordKLAX.Coord, 0},
+		{route.legDistance(0) + 100, RhumbDestination(coordKLAX.Coord, RhumbBearing(coordKLAX.Coord, coordKJFK.Coord), 100), 1},
+		{route.Distance() + 10, coordKJFK.Coord, 1},
+	}
+	for _, v := range pointsAtDistance {
+		result, leg := route.PointAtDistance(v.distance)
+		if Distance(result, v.expected) > 0.0001 || leg != v.leg {
+			t.Fatalf("Expected: %v on leg %v, received %v on leg %v", v.expected, v.leg, result, leg)
+		}
+	}
+
+	if result, leg := NewMultiPointRoute([]NamedCoordinate{coordKSFO}).PointAtDistance(100); result != coordKSFO.Coord || leg != 0 {
+		t.Fatalf("Expected: %v on leg 0, received %v on leg %v", coordKSFO.Coord, result, leg)
+	}
+	if result, leg := (LegTypedRoute{}).PointAtDistance(100); result != (Coordinate{}) || leg != -1 {
+		t.Fatalf("Expected: no point on leg -1, received %v on leg %v", result, leg)
+	}
+}
@@ -0,0 +1,70 @@
+package greatcircle
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+/*
+LegTrace describes the search of one leg of a route for points of interest.
+*/
+type LegTrace struct {
+	// Leg is the index of the leg, from route point Leg to Leg+1
+	Leg      int
+	From, To Coordinate
+	// Candidates is the number of points of interest tested against the leg
+	Candidates int
+	// Hits is the number of points of interest within reach of the leg,
+	// including any also within reach of an earlier leg
+	Hits    int
+	Elapsed time.Duration
+}
+
+/*
+POIOption configures a search for points of interest along a route,
+such as MultiPointRoutePOIS.
+*/
+type POIOption func(*poiOptions)
+
+type poiOptions struct {
+	observers []func(LegTrace)
+}
+
+/*
+WithObserver calls observer with a LegTrace as each leg of the route is searched.
+*/
+func WithObserver(observer func(LegTrace)) POIOption {
+	return func(options *poiOptions) {
+		options.observers = append(options.observers, observer)
+	}
+}
+
+/*
+WithLogger logs a LegTrace at debug level to logger as each leg of the route is searched.
+*/
+func WithLogger(logger *slog.Logger) POIOption {
+	return WithObserver(func(trace LegTrace) {
+		logger.LogAttrs(context.Background(), slog.LevelDebug, "greatcircle: searched leg",
+			slog.Int("leg", trace.Leg),
+			slog.Any("from", trace.From),
+			slog.Any("to", trace.To),
+			slog.Int("candidates", trace.Candidates),
+			slog.Int("hits", trace.Hits),
+			slog.Duration("elapsed", trace.Elapsed))
+	})
+}
+
+func newPOIOptions(opts []POIOption) poiOptions {
+	var options poiOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+func (options poiOptions) trace(trace LegTrace) {
+	for _, observer := range options.observers {
+		observer(trace)
+	}
+}
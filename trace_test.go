@@ -0,0 +1,71 @@
+package greatcircle
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"math/rand"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestMultiPointRoutePOISObserver(t *testing.T) {
+	route := []Coordinate{coordKSFO.Coord, coordKLAX.Coord, coordKJFK.Coord}
+	pois := []Coordinate{coordKSJC.Coord, coordKMAE.Coord, coordKMOD.Coord}
+	var traces []LegTrace
+	results := MultiPointRoutePOIS(route, pois, 60, WithObserver(func(trace LegTrace) {
+		traces = append(traces, trace)
+	}))
+	if len(traces) != 2 {
+		t.Fatalf("Expected a trace per leg, received %v", traces)
+	}
+	if traces[0].Leg != 0 || traces[0].From != coordKSFO.Coord || traces[0].To != coordKLAX.Coord ||
+		traces[0].Candidates != 3 || traces[0].Hits != len(results) || traces[0].Elapsed < 0 {
+		t.Fatalf("Expected a trace of the first leg, received %v", traces[0])
+	}
+	if traces[1].Leg != 1 || traces[1].Hits != 0 {
+		t.Fatalf("Expected a trace of the second leg, received %v", traces[1])
+	}
+
+	// the index only tests the candidates it cannot rule out
+	traces = nil
+	NewIndex(randomCoordinates(rand.New(rand.NewSource(7)), 1000)).MultiPointRoutePOIS(route, 60, WithObserver(func(trace LegTrace) {
+		traces = append(traces, trace)
+	}))
+	if len(traces) != 2 || traces[0].Candidates >= 1000 {
+		t.Fatalf("Expected pruned candidates, received %v", traces)
+	}
+
+	traces = nil
+	NewMultiPointRoute([]NamedCoordinate{coordKSFO, coordKLAX, coordKJFK}).POIS(pois, 60, WithObserver(func(trace LegTrace) {
+		traces = append(traces, trace)
+	}))
+	if len(traces) != 2 || traces[0].Candidates != 3 || traces[0].Hits != len(results) {
+		t.Fatalf("Expected a trace per leg, received %v", traces)
+	}
+}
+
+func TestMultiPointRoutePOISLogger(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	// nothing is printed to stdout
+	stdout := os.Stdout
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Error creating pipe; error %v", err)
+	}
+	os.Stdout = writer
+	MultiPointRoutePOIS([]Coordinate{coordKSFO.Coord, coordKLAX.Coord}, []Coordinate{coordKSJC.Coord}, 60, WithLogger(logger))
+	os.Stdout = stdout
+	writer.Close()
+	printed, _ := io.ReadAll(reader)
+	if len(printed) != 0 {
+		t.Fatalf("Expected nothing printed, received %s", printed)
+	}
+
+	if !strings.Contains(logs.String(), "leg=0") || !strings.Contains(logs.String(), "candidates=1 hits=1") {
+		t.Fatalf("Expected the leg to be logged, received %s", logs.String())
+	}
+}
@@ -0,0 +1,255 @@
+package greatcircle
+
+import (
+	"strconv"
+)
+
+/*
+Angle is an angle, such as a bearing or a latitude, stored in radians.
+
+	bearing := Degrees(270)
+	fmt.Println(bearing.Radians())
+*/
+type Angle float64
+
+/*
+Radians creates an Angle from radians.
+*/
+func Radians(radians float64) Angle {
+	return Angle(radians)
+}
+
+/*
+Degrees creates an Angle from decimal degrees.
+*/
+func Degrees(degrees float64) Angle {
+	return Angle(DegreesToRadians(degrees))
+}
+
+/*
+Radians returns the Angle in radians.
+*/
+func (angle Angle) Radians() float64 {
+	return float64(angle)
+}
+
+/*
+Degrees returns the Angle in decimal degrees.
+*/
+func (angle Angle) Degrees() float64 {
+	return RadiansToDegrees(float64(angle))
+}
+
+/*
+Length returns the length of the arc the Angle subtends at the centre of the Earth.
+*/
+func (angle Angle) Length() Length {
+	return Length(RadiansToNM(float64(angle)))
+}
+
+func (angle Angle) String() string {
+	return strconv.FormatFloat(angle.Degrees(), 'f', -1, 64) + "°"
+}
+
+/*
+Length is a distance over the Earth, stored in nautical miles. Lengths in
+other units are made by multiplying the unit constants:
+
+	distance := 25 * Kilometre
+	fmt.Println(distance.StatuteMiles())
+*/
+type Length float64
+
+// Units of Length
+const (
+	NauticalMile Length = 1
+	Metre        Length = 1 / metresPerNM
+	Kilometre           = 1000 * Metre
+	Foot                = 0.3048 * Metre
+	StatuteMile         = 5280 * Foot
+)
+
+/*
+NauticalMiles returns the Length in nautical miles.
+*/
+func (length Length) NauticalMiles() float64 {
+	return float64(length)
+}
+
+/*
+Metres returns the Length in metres.
+*/
+func (length Length) Metres() float64 {
+	return float64(length / Metre)
+}
+
+/*
+Kilometres returns the Length in kilometres.
+*/
+func (length Length) Kilometres() float64 {
+	return float64(length / Kilometre)
+}
+
+/*
+StatuteMiles returns the Length in statute miles.
+*/
+func (length Length) StatuteMiles() float64 {
+	return float64(length / StatuteMile)
+}
+
+/*
+Feet returns the Length in feet.
+*/
+func (length Length) Feet() float64 {
+	return float64(length / Foot)
+}
+
+/*
+Angle returns the angle the Length subtends at the centre of the Earth.
+*/
+func (length Length) Angle() Angle {
+	return Angle(NMToRadians(float64(length)))
+}
+
+func (length Length) String() string {
+	return strconv.FormatFloat(float64(length), 'f', -1, 64) + " NM"
+}
+
+/*
+The methods and functions below are the typed equivalents of the package level
+functions that take and return bare radians and nautical miles. Each measuring
+from a Coordinate is a method of it, named for the function with To the other
+Coordinate, At a bearing and distance, or From the course it is measured from.
+Those searching within a distance of a route or Coordinate are named for the
+function with Within, or Radius for Index.Within, and a route's Distance is
+its Length.
+*/
+
+/*
+DistanceTo is the great circle distance from coord to another Coordinate; see Distance.
+*/
+func (coord Coordinate) DistanceTo(another Coordinate) Length {
+	return Length(Distance(coord, another))
+}
+
+/*
+InitialBearingTo is the initial true course of the great circle from coord to
+another Coordinate; see InitialBearing.
+*/
+func (coord Coordinate) InitialBearingTo(another Coordinate) Angle {
+	return Angle(InitialBearing(coord, another))
+}
+
+/*
+FinalBearingTo is the true course on arrival at another Coordinate along the
+great circle from coord; see FinalBearing.
+*/
+func (coord Coordinate) FinalBearingTo(another Coordinate) Angle {
+	return Angle(FinalBearing(coord, another))
+}
+
+/*
+RhumbDistanceTo is the distance along the rhumb line from coord to another
+Coordinate; see RhumbDistance.
+*/
+func (coord Coordinate) RhumbDistanceTo(another Coordinate) Length {
+	return Length(RhumbDistance(coord, another))
+}
+
+/*
+RhumbBearingTo is the constant true course of the rhumb line from coord to
+another Coordinate; see RhumbBearing.
+*/
+func (coord Coordinate) RhumbBearingTo(another Coordinate) Angle {
+	return Angle(RhumbBearing(coord, another))
+}
+
+/*
+DestinationAt is the Coordinate reached by travelling distance along the great
+circle that commences on the initial true course bearing; see Destination.
+*/
+func (coord Coordinate) DestinationAt(bearing Angle, distance Length) Coordinate {
+	return coord.Destination(float64(bearing), float64(distance))
+}
+
+/*
+RhumbDestinationAt is the Coordinate reached by travelling distance on the
+constant true course bearing; see RhumbDestination.
+*/
+func (coord Coordinate) RhumbDestinationAt(bearing Angle, distance Length) Coordinate {
+	return RhumbDestination(coord, float64(bearing), float64(distance))
+}
+
+/*
+DestinationAt is the Coordinate reached by travelling distance along the
+Radial; see Radial.Destination.
+*/
+func (radial Radial) DestinationAt(distance Length) Coordinate {
+	return radial.Destination(float64(distance))
+}
+
+/*
+CrossTrackErrorFrom is the distance of coord off the course from
+routeStartCoord to routeEndCoord; positive is right of course, negative left.
+See CrossTrackError.
+*/
+func (coord Coordinate) CrossTrackErrorFrom(routeStartCoord, routeEndCoord Coordinate) Length {
+	return Angle(CrossTrackError(routeStartCoord, routeEndCoord, coord)).Length()
+}
+
+/*
+AlongTrackDistanceFrom is the distance from routeStartCoord along the course
+towards routeEndCoord to the point abeam coord. Unlike AlongTrackDistance it
+is negative when that point is behind routeStartCoord.
+*/
+func (coord Coordinate) AlongTrackDistanceFrom(routeStartCoord, routeEndCoord Coordinate) Length {
+	return Angle(alongTrack(routeStartCoord, routeEndCoord, coord)).Length()
+}
+
+/*
+PointInReachWithin determines if point3 is within distance of the route from
+point1 to point2; see PointInReach.
+*/
+func PointInReachWithin(point1, point2, point3 Coordinate, distance Length) bool {
+	return PointInReach(point1, point2, point3, distance.NauticalMiles())
+}
+
+/*
+PointsInReachWithin filters a list of Coordinates to those within distance of
+the (routeStartCoord, routeEndCoord) route, nearest first; see PointsInReach.
+*/
+func PointsInReachWithin(routeStartCoord, routeEndCoord Coordinate, distance Length, coords []Coordinate) []Coordinate {
+	return PointsInReach(routeStartCoord, routeEndCoord, distance.NauticalMiles(), coords)
+}
+
+/*
+MultiPointRoutePOISWithin returns the points of interest within distance of the
+multi point route; see MultiPointRoutePOIS.
+*/
+func MultiPointRoutePOISWithin(routePoints, pois []Coordinate, distance Length, opts ...POIOption) []MultiPoint {
+	return MultiPointRoutePOIS(routePoints, pois, distance.NauticalMiles(), opts...)
+}
+
+/*
+WithinRadius returns the points of the Index within radius of coord; see
+Index.Within.
+*/
+func (index *Index) WithinRadius(coord Coordinate, radius Length) []NamedCoordinate {
+	return index.Within(coord, radius.NauticalMiles())
+}
+
+/*
+Length is the total length of the route, each leg a great circle; see
+MultiPointRoute.Distance.
+*/
+func (route MultiPointRoute) Length() Length {
+	return Length(route.Distance())
+}
+
+/*
+Length is the total length of the route, following each leg according to its
+LegType; see LegTypedRoute.Distance.
+*/
+func (route LegTypedRoute) Length() Length {
+	return Length(route.Distance())
+}
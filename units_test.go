@@ -0,0 +1,122 @@
+package greatcircle
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+var lengths = []struct {
+	length   Length
+	value    func(Length) float64
+	expected float64
+}{
+	{NauticalMile, Length.Metres, 1852},
+	{NauticalMile, Length.Kilometres, 1.852},
+	{NauticalMile, Length.Feet, 6076.115486},
+	{NauticalMile, Length.StatuteMiles, 1.150779},
+	{StatuteMile, Length.Feet, 5280},
+	{StatuteMile, Length.NauticalMiles, 0.868976},
+	{100 * Kilometre, Length.NauticalMiles, 53.995680},
+	{1000 * Foot, Length.Metres, 304.8},
+}
+
+func TestLengthUnits(t *testing.T) {
+	for _, v := range lengths {
+		if result := v.value(v.length); math.Abs(result-v.expected) > 0.000001 {
+			t.Fatalf("Expected: %v, received %v", v.expected, result)
+		}
+	}
+	if result := (60 * NauticalMile).Angle().Degrees(); math.Abs(result-1) > 1e-12 {
+		t.Fatalf("Expected: 1, received %v", result)
+	}
+	if result := (25 * NauticalMile).String(); result != "25 NM" {
+		t.Fatalf("Expected: 25 NM, received %v", result)
+	}
+}
+
+func TestAngleUnits(t *testing.T) {
+	if result := Degrees(180).Radians(); result != math.Pi {
+		t.Fatalf("Expected: %v, received %v", math.Pi, result)
+	}
+	if result := Radians(math.Pi / 2).Degrees(); result != 90 {
+		t.Fatalf("Expected: 90, received %v", result)
+	}
+	if result := Degrees(1).Length(); math.Abs(float64(result)-60) > 1e-12 {
+		t.Fatalf("Expected: 60 NM, received %v", result)
+	}
+	if result := Degrees(270).String(); result != "270°" {
+		t.Fatalf("Expected: 270°, received %v", result)
+	}
+}
+
+func TestTypedFunctions(t *testing.T) {
+	ksfo, klax := coordKSFO.Coord, coordKLAX.Coord
+	if result := ksfo.DistanceTo(klax); result.NauticalMiles() != Distance(ksfo, klax) {
+		t.Fatalf("Expected: %v, received %v", Distance(ksfo, klax), result)
+	}
+	if result := ksfo.InitialBearingTo(klax); result.Radians() != InitialBearing(ksfo, klax) {
+		t.Fatalf("Expected: %v, received %v", InitialBearing(ksfo, klax), result)
+	}
+	if result := ksfo.FinalBearingTo(klax); result.Radians() != FinalBearing(ksfo, klax) {
+		t.Fatalf("Expected: %v, received %v", FinalBearing(ksfo, klax), result)
+	}
+	if result := klax.RhumbBearingTo(coordKJFK.Coord); math.Abs(result.Degrees()-79.32) > 0.01 {
+		t.Fatalf("Expected: 79.32, received %v", result.Degrees())
+	}
+	if result := klax.RhumbDistanceTo(coordKJFK.Coord); math.Abs(result.NauticalMiles()-2164.6) > 0.1 {
+		t.Fatalf("Expected: 2164.6, received %v", result)
+	}
+
+	// the Aviation Formulary's 100 NM from LAX on the 66 degree radial
+	result := klax.DestinationAt(Degrees(66), 185.2*Kilometre)
+	if !result.Equal(klax.Destination(DegreesToRadians(66), 100)) {
+		t.Fatalf("Expected: %v, received %v", klax.Destination(DegreesToRadians(66), 100), result)
+	}
+	if result := (Radial{klax, DegreesToRadians(66)}).DestinationAt(100 * NauticalMile); !result.Equal(klax.Destination(DegreesToRadians(66), 100)) {
+		t.Fatalf("Expected: %v, received %v", klax.Destination(DegreesToRadians(66), 100), result)
+	}
+	if result := klax.RhumbDestinationAt(Degrees(90), 60*NauticalMile); !result.Equal(RhumbDestination(klax, math.Pi/2, 60)) {
+		t.Fatalf("Expected: %v, received %v", RhumbDestination(klax, math.Pi/2, 60), result)
+	}
+
+	if result := coordKMOD.Coord.CrossTrackErrorFrom(ksfo, klax); result.NauticalMiles() != RadiansToNM(CrossTrackError(ksfo, klax, coordKMOD.Coord)) {
+		t.Fatalf("Expected: %v, received %v", RadiansToNM(CrossTrackError(ksfo, klax, coordKMOD.Coord)), result)
+	}
+	if result := coordKSJC.Coord.AlongTrackDistanceFrom(ksfo, klax); math.Abs(result.NauticalMiles()-RadiansToNM(AlongTrackDistance(ksfo, klax, coordKSJC.Coord))) > 1e-9 {
+		t.Fatalf("Expected: %v, received %v", RadiansToNM(AlongTrackDistance(ksfo, klax, coordKSJC.Coord)), result)
+	}
+	// behind the start of the route
+	if result := ksfo.AlongTrackDistanceFrom(klax, coordKJFK.Coord); result >= 0 {
+		t.Fatalf("Expected a negative along track distance, received %v", result)
+	}
+}
+
+func TestTypedSearches(t *testing.T) {
+	ksfo, klax := coordKSFO.Coord, coordKLAX.Coord
+	pois := []Coordinate{coordKMOD.Coord, coordKSJC.Coord, coordKMAE.Coord}
+	// 50 NM is 92.6 km
+	if result, expected := PointsInReachWithin(ksfo, klax, 92.6*Kilometre, pois), PointsInReach(ksfo, klax, 50, pois); !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Expected: %v, received %v", expected, result)
+	}
+	if PointInReachWithin(ksfo, klax, coordKSJC.Coord, 50*NauticalMile) != PointInReach(ksfo, klax, coordKSJC.Coord, 50) {
+		t.Fatalf("Expected: %v, received %v", PointInReach(ksfo, klax, coordKSJC.Coord, 50), !PointInReach(ksfo, klax, coordKSJC.Coord, 50))
+	}
+	route := []Coordinate{ksfo, klax, coordKJFK.Coord}
+	if result, expected := MultiPointRoutePOISWithin(route, pois, 50*NauticalMile), MultiPointRoutePOIS(route, pois, 50); !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Expected: %v, received %v", expected, result)
+	}
+	index := NewIndex(pois)
+	if result, expected := index.WithinRadius(ksfo, 100*NauticalMile), index.Within(ksfo, 100); !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Expected: %v, received %v", expected, result)
+	}
+
+	named := NewMultiPointRoute([]NamedCoordinate{coordKSFO, coordKLAX, coordKJFK})
+	if result := named.Length(); result.NauticalMiles() != named.Distance() {
+		t.Fatalf("Expected: %v, received %v", named.Distance(), result)
+	}
+	legTyped := named.WithLegTypes(GreatCircleLeg, RhumbLineLeg)
+	if result := legTyped.Length(); result.NauticalMiles() != legTyped.Distance() || result == named.Length() {
+		t.Fatalf("Expected: %v, received %v", legTyped.Distance(), result)
+	}
+}
@@ -0,0 +1,280 @@
+package greatcircle
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+/*
+UTM is a position on the Universal Transverse Mercator grid, or within 6° of a
+pole on the Universal Polar Stereographic grid, on the WGS84 ellipsoid.
+
+UTM divides the Earth between 80°S and 84°N into 60 zones, each 6° of longitude
+wide, and measures the Easting and Northing in metres from a false origin in
+each zone and hemisphere. Zone is 0 for UPS, where North selects the polar
+grid.
+*/
+type UTM struct {
+	// Zone is 1 to 60, or 0 for UPS
+	Zone int
+	// North is true in the northern hemisphere
+	North bool
+	// Easting is in metres, 500,000 on the central meridian of a UTM zone
+	Easting float64
+	// Northing is in metres from the equator, plus 10,000,000 in the southern hemisphere
+	Northing float64
+}
+
+// ErrGridReference is returned when a UTM position, grid reference or location code does not describe a position on earth.
+var ErrGridReference = errors.New("invalid grid reference")
+
+const (
+	utmScale         = 0.9996
+	utmFalseEasting  = 500000.0
+	utmFalseNorthing = 10000000.0
+	upsScale         = 0.994
+	upsFalseOrigin   = 2000000.0
+)
+
+/*
+ToUTM converts the Coordinate to UTM, following the exceptions for southwest
+Norway and Svalbard, or UPS north of 84°N and south of 80°S. A *CoordinateError
+is returned if the Coordinate is not valid.
+*/
+func (coord Coordinate) ToUTM() (UTM, error) {
+	coord, err := coord.Normalize()
+	if err != nil {
+		return UTM{}, err
+	}
+	latitude, longitude := coord.LatLonDegrees()
+	if latitude > 84 || latitude < -80 {
+		return upsForward(coord), nil
+	}
+	return utmForward(coord, utmZone(latitude, longitude)), nil
+}
+
+/*
+Coordinate converts the UTM or UPS position back to a Coordinate. An error
+wrapping ErrGridReference is returned if the Zone is not valid, or the position
+is not on the Earth.
+*/
+func (utm UTM) Coordinate() (Coordinate, error) {
+	if utm.Zone < 0 || utm.Zone > 60 || math.IsNaN(utm.Easting) || math.IsNaN(utm.Northing) ||
+		math.IsInf(utm.Easting, 0) || math.IsInf(utm.Northing, 0) {
+		return Coordinate{}, fmt.Errorf("greatcircle: UTM %v: %w", utm, ErrGridReference)
+	}
+	if utm.Zone == 0 {
+		return upsInverse(utm)
+	}
+	return utmInverse(utm)
+}
+
+/*
+String returns the UTM position as the zone, hemisphere and the easting and
+northing to the metre, such as "31N 166021 0"; or for UPS without a zone,
+such as "N 2000000 2000000".
+*/
+func (utm UTM) String() string {
+	hemisphere := "S"
+	if utm.North {
+		hemisphere = "N"
+	}
+	zone := ""
+	if utm.Zone != 0 {
+		zone = strconv.Itoa(utm.Zone)
+	}
+	return zone + hemisphere + " " + strconv.FormatFloat(math.Floor(utm.Easting), 'f', 0, 64) + " " +
+		strconv.FormatFloat(math.Floor(utm.Northing), 'f', 0, 64)
+}
+
+// utmZone is the zone of a latitude and longitude in degrees, East positive
+func utmZone(latitude, longitude float64) int {
+	zone := int(math.Floor((longitude+180)/6)) + 1
+	if zone > 60 {
+		zone = 60
+	}
+	// southwest Norway is in a wider zone 32
+	if latitude >= 56 && latitude < 64 && longitude >= 3 && longitude < 12 {
+		return 32
+	}
+	// Svalbard is in zones 31, 33, 35 and 37 alone
+	if latitude >= 72 && longitude >= 0 && longitude < 42 {
+		switch {
+		case longitude < 9:
+			return 31
+		case longitude < 21:
+			return 33
+		case longitude < 33:
+			return 35
+		default:
+			return 37
+		}
+	}
+	return zone
+}
+
+// utmCentralMeridian is the longitude in radians, East positive, of the centre of a zone
+func utmCentralMeridian(zone int) float64 {
+	return DegreesToRadians(float64(zone-1)*6 - 180 + 3)
+}
+
+/*
+The Krüger series for the transverse Mercator projection, to sixth order in
+the third flattening n, as given by Karney, "Transverse Mercator with an
+accuracy of a few nanometers", 2011; accurate to well under a millimetre
+within a zone.
+*/
+type krugerSeries struct {
+	// a is the radius of the rectifying sphere, in metres
+	a           float64
+	e           float64
+	alpha, beta [7]float64
+}
+
+var wgs84Kruger = newKrugerSeries(WGS84)
+
+func newKrugerSeries(ellipsoid Ellipsoid) krugerSeries {
+	f := ellipsoid.Flattening
+	n := f / (2 - f)
+	n2, n3, n4, n5, n6 := n*n, n*n*n, n*n*n*n, n*n*n*n*n, n*n*n*n*n*n
+	return krugerSeries{
+		a: ellipsoid.SemiMajorAxis / (1 + n) * (1 + n2/4 + n4/64 + n6/256),
+		e: math.Sqrt(f * (2 - f)),
+		alpha: [7]float64{0,
+			n/2 - 2*n2/3 + 5*n3/16 + 41*n4/180 - 127*n5/288 + 7891*n6/37800,
+			13*n2/48 - 3*n3/5 + 557*n4/1440 + 281*n5/630 - 1983433*n6/1935360,
+			61*n3/240 - 103*n4/140 + 15061*n5/26880 + 167603*n6/181440,
+			49561*n4/161280 - 179*n5/168 + 6601661*n6/7257600,
+			34729*n5/80640 - 3418889*n6/1995840,
+			212378941 * n6 / 319334400,
+		},
+		beta: [7]float64{0,
+			n/2 - 2*n2/3 + 37*n3/96 - n4/360 - 81*n5/512 + 96199*n6/604800,
+			n2/48 + n3/15 - 437*n4/1440 + 46*n5/105 - 1118711*n6/3870720,
+			17*n3/480 - 37*n4/840 - 209*n5/4480 + 5569*n6/90720,
+			4397*n4/161280 - 11*n5/504 - 830251*n6/7257600,
+			4583*n5/161280 - 108847*n6/3991680,
+			20648693 * n6 / 638668800,
+		},
+	}
+}
+
+// conformalTangent converts the tangent of a latitude to the tangent of its conformal latitude
+func (series krugerSeries) conformalTangent(tau float64) float64 {
+	sigma := math.Sinh(series.e * math.Atanh(series.e*tau/math.Sqrt(1+tau*tau)))
+	return tau*math.Sqrt(1+sigma*sigma) - sigma*math.Sqrt(1+tau*tau)
+}
+
+func utmForward(coord Coordinate, zone int) UTM {
+	series := wgs84Kruger
+	latitude, longitude := coord.LatLonRadians()
+	lambda := longitude - utmCentralMeridian(zone)
+	tauPrime := series.conformalTangent(math.Tan(latitude))
+	xiPrime := math.Atan2(tauPrime, math.Cos(lambda))
+	etaPrime := math.Asinh(math.Sin(lambda) / math.Sqrt(tauPrime*tauPrime+math.Cos(lambda)*math.Cos(lambda)))
+
+	xi, eta := xiPrime, etaPrime
+	for j := 1; j <= 6; j++ {
+		k := 2 * float64(j)
+		xi = xi + series.alpha[j]*math.Sin(k*xiPrime)*math.Cosh(k*etaPrime)
+		eta = eta + series.alpha[j]*math.Cos(k*xiPrime)*math.Sinh(k*etaPrime)
+	}
+	utm := UTM{
+		Zone:     zone,
+		North:    latitude >= 0,
+		Easting:  utmScale*series.a*eta + utmFalseEasting,
+		Northing: utmScale * series.a * xi,
+	}
+	if !utm.North {
+		utm.Northing = utm.Northing + utmFalseNorthing
+	}
+	return utm
+}
+
+func utmInverse(utm UTM) (Coordinate, error) {
+	series := wgs84Kruger
+	northing := utm.Northing
+	if !utm.North {
+		northing = northing - utmFalseNorthing
+	}
+	xi := northing / (utmScale * series.a)
+	eta := (utm.Easting - utmFalseEasting) / (utmScale * series.a)
+	xiPrime, etaPrime := xi, eta
+	for j := 1; j <= 6; j++ {
+		k := 2 * float64(j)
+		xiPrime = xiPrime - series.beta[j]*math.Sin(k*xi)*math.Cosh(k*eta)
+		etaPrime = etaPrime - series.beta[j]*math.Cos(k*xi)*math.Sinh(k*eta)
+	}
+	sinhEtaPrime := math.Sinh(etaPrime)
+	tauPrime := math.Sin(xiPrime) / math.Sqrt(sinhEtaPrime*sinhEtaPrime+math.Cos(xiPrime)*math.Cos(xiPrime))
+
+	// Newton's method for the latitude whose conformal latitude is tauPrime
+	e2 := series.e * series.e
+	tau := tauPrime
+	for i := 0; i < 10; i++ {
+		tauI := series.conformalTangent(tau)
+		delta := (tauPrime - tauI) / math.Sqrt(1+tauI*tauI) *
+			(1 + (1-e2)*tau*tau) / ((1 - e2) * math.Sqrt(1+tau*tau))
+		tau = tau + delta
+		if math.Abs(delta) < 1e-12 {
+			break
+		}
+	}
+	latitude := math.Atan(tau)
+	longitude := utmCentralMeridian(utm.Zone) + math.Atan2(sinhEtaPrime, math.Cos(xiPrime))
+	return FromLatLonRadians(latitude, longitude)
+}
+
+/*
+The polar stereographic projection, from Snyder, "Map Projections: A Working
+Manual", 1987.
+*/
+
+/*
+upsProjection returns the eccentricity, and the scale of the projection:
+2a k0 / sqrt((1+e)^(1+e) (1-e)^(1-e))
+*/
+func upsProjection() (scale, e float64) {
+	e = wgs84Kruger.e
+	return 2 * WGS84.SemiMajorAxis * upsScale / math.Sqrt(math.Pow(1+e, 1+e)*math.Pow(1-e, 1-e)), e
+}
+
+func upsForward(coord Coordinate) UTM {
+	scale, e := upsProjection()
+	latitude, longitude := coord.LatLonRadians()
+	north := latitude >= 0
+	phi := math.Abs(latitude)
+	t := math.Tan(math.Pi/4-phi/2) / math.Pow((1-e*math.Sin(phi))/(1+e*math.Sin(phi)), e/2)
+	rho := scale * t
+	utm := UTM{Zone: 0, North: north, Easting: upsFalseOrigin + rho*math.Sin(longitude)}
+	if north {
+		utm.Northing = upsFalseOrigin - rho*math.Cos(longitude)
+	} else {
+		utm.Northing = upsFalseOrigin + rho*math.Cos(longitude)
+	}
+	return utm
+}
+
+func upsInverse(utm UTM) (Coordinate, error) {
+	scale, e := upsProjection()
+	dx, dy := utm.Easting-upsFalseOrigin, utm.Northing-upsFalseOrigin
+	t := math.Hypot(dx, dy) / scale
+	phi := math.Pi/2 - 2*math.Atan(t)
+	for i := 0; i < 10; i++ {
+		next := math.Pi/2 - 2*math.Atan(t*math.Pow((1-e*math.Sin(phi))/(1+e*math.Sin(phi)), e/2))
+		done := math.Abs(next-phi) < 1e-14
+		phi = next
+		if done {
+			break
+		}
+	}
+	longitude := math.Atan2(dx, dy)
+	if utm.North {
+		longitude = math.Atan2(dx, -dy)
+	} else {
+		phi = -phi
+	}
+	return FromLatLonRadians(phi, longitude)
+}
@@ -0,0 +1,77 @@
+package greatcircle
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestToUTM(t *testing.T) {
+	cases := []struct {
+		latitude, longitude float64
+		expected            UTM
+	}{
+		{0, 0, UTM{31, true, 166021.443, 0}},
+		// the Eiffel Tower
+		{DegreeUnitsToDecimalDegree(48, 51, 29.5), DegreeUnitsToDecimalDegree(2, 17, 40.2), UTM{31, true, 448251.79, 5411932.06}},
+		{-33.946, 151.177, UTM{56, false, 331532.66, 6242334.51}},
+		{37.616667, -122.366667, UTM{10, true, 555893.43, 4163473.37}},
+		// southwest Norway and Svalbard
+		{60, 5, UTM{32, true, 0, 0}},
+		{78, 8, UTM{31, true, 0, 0}},
+		{78, 10, UTM{33, true, 0, 0}},
+		{78, 40, UTM{37, true, 0, 0}},
+		{84, 0, UTM{31, true, 0, 0}},
+		{-80, 0, UTM{31, false, 0, 0}},
+		// UPS
+		{90, 0, UTM{0, true, 2000000, 2000000}},
+		{-90, 0, UTM{0, false, 2000000, 2000000}},
+		{84.5, 0, UTM{0, true, 2000000, 1388918.64}},
+	}
+	for _, c := range cases {
+		coord, _ := FromLatLonDegrees(c.latitude, c.longitude)
+		result, err := coord.ToUTM()
+		if err != nil {
+			t.Fatalf("Error converting %v,%v; error %v", c.latitude, c.longitude, err)
+		}
+		if result.Zone != c.expected.Zone || result.North != c.expected.North {
+			t.Fatalf("Expected: %v, received %v for %v,%v", c.expected, result, c.latitude, c.longitude)
+		}
+		if c.expected.Easting != 0 && (math.Abs(result.Easting-c.expected.Easting) > 0.01 || math.Abs(result.Northing-c.expected.Northing) > 1) {
+			t.Fatalf("Expected: %v, received %v for %v,%v", c.expected, result, c.latitude, c.longitude)
+		}
+	}
+
+	coord, _ := FromLatLonDegrees(0, 0)
+	result, _ := coord.ToUTM()
+	if expected := "31N 166021 0"; result.String() != expected {
+		t.Fatalf("Expected: %s, received %s", expected, result)
+	}
+	if _, err := (Coordinate{math.NaN(), 0}).ToUTM(); !errors.Is(err, ErrInvalidLatitude) {
+		t.Fatalf("Expected: %v, received %v", ErrInvalidLatitude, err)
+	}
+	if _, err := (UTM{61, true, 500000, 0}).Coordinate(); !errors.Is(err, ErrGridReference) {
+		t.Fatalf("Expected: %v, received %v", ErrGridReference, err)
+	}
+}
+
+func TestUTMRoundTrip(t *testing.T) {
+	random := rand.New(rand.NewSource(19))
+	for i := 0; i < 2000; i++ {
+		latitude := random.Float64()*180 - 90
+		longitude := random.Float64()*360 - 180
+		coord, _ := FromLatLonDegrees(latitude, longitude)
+		utm, err := coord.ToUTM()
+		if err != nil {
+			t.Fatalf("Error converting %v; error %v", coord, err)
+		}
+		result, err := utm.Coordinate()
+		if err != nil {
+			t.Fatalf("Error converting %v; error %v", utm, err)
+		}
+		if math.Abs(result.Latitude-coord.Latitude) > 1e-11 || math.Abs(result.Longitude-coord.Longitude)*math.Cos(coord.Latitude) > 1e-11 {
+			t.Fatalf("Expected: %v, received %v through %v", coord, result, utm)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package greatcircle
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Errors wrapped by CoordinateError describing why a Coordinate is not valid.
+var (
+	ErrInvalidLatitude  = errors.New("latitude must be between -pi/2 and pi/2 radians")
+	ErrInvalidLongitude = errors.New("longitude must be finite and between -pi and pi radians")
+)
+
+/*
+CoordinateError describes a Coordinate that does not describe a position on earth.
+*/
+type CoordinateError struct {
+	Coordinate Coordinate
+	// Err is ErrInvalidLatitude or ErrInvalidLongitude
+	Err error
+}
+
+func (e *CoordinateError) Error() string {
+	return fmt.Sprintf("greatcircle: invalid coordinate %v: %v", e.Coordinate, e.Err)
+}
+
+func (e *CoordinateError) Unwrap() error {
+	return e.Err
+}
+
+/*
+overshoot is how far beyond a pole a latitude may be, due to
+floating point error, and still be treated as the pole.
+*/
+const overshoot = 1e-9
+
+/*
+NewCoordinateRadians creates a normalised Coordinate from a latitude and
+longitude in radians, or returns a *CoordinateError if they are not valid.
+*/
+func NewCoordinateRadians(latitude, longitude float64) (Coordinate, error) {
+	return Coordinate{latitude, longitude}.Normalize()
+}
+
+/*
+NewCoordinateDegrees creates a normalised Coordinate from a latitude and
+longitude in decimal degrees, or returns a *CoordinateError if they are not valid.
+
+North and West are positive.
+*/
+func NewCoordinateDegrees(latitude, longitude float64) (Coordinate, error) {
+	return NewCoordinateRadians(DegreesToRadians(latitude), DegreesToRadians(longitude))
+}
+
+/*
+FromLatLonDegrees creates a normalised Coordinate from a latitude and longitude
+in decimal degrees following the usual convention of GPS receivers, GeoJSON and
+most other software: North and East positive. A *CoordinateError is returned if
+they are not valid.
+
+Use it, and LatLonDegrees, to convert once at the boundary with such systems.
+*/
+func FromLatLonDegrees(latitude, longitude float64) (Coordinate, error) {
+	return NewCoordinateDegrees(latitude, -longitude)
+}
+
+/*
+FromLatLonRadians creates a normalised Coordinate from a latitude and longitude
+in radians, North and East positive. A *CoordinateError is returned if they are
+not valid.
+*/
+func FromLatLonRadians(latitude, longitude float64) (Coordinate, error) {
+	return NewCoordinateRadians(latitude, -longitude)
+}
+
+/*
+LatLonDegrees returns the latitude and longitude of the Coordinate in decimal
+degrees, North and East positive.
+*/
+func (coord Coordinate) LatLonDegrees() (latitude, longitude float64) {
+	return RadiansToDegrees(coord.Latitude), -RadiansToDegrees(coord.Longitude)
+}
+
+/*
+LatLonRadians returns the latitude and longitude of the Coordinate in radians,
+North and East positive.
+*/
+func (coord Coordinate) LatLonRadians() (latitude, longitude float64) {
+	return coord.Latitude, -coord.Longitude
+}
+
+/*
+Validate returns a *CoordinateError if the Coordinate does not describe a
+position on earth: a latitude beyond a pole, or a latitude or longitude that
+is NaN or infinite.
+
+Longitudes outside (-pi, pi] are valid; see Normalize.
+*/
+func (coord Coordinate) Validate() error {
+	if math.IsNaN(coord.Latitude) || math.Abs(coord.Latitude) > math.Pi/2+overshoot {
+		return &CoordinateError{coord, ErrInvalidLatitude}
+	}
+	if math.IsNaN(coord.Longitude) || math.IsInf(coord.Longitude, 0) {
+		return &CoordinateError{coord, ErrInvalidLongitude}
+	}
+	return nil
+}
+
+/*
+Normalize returns the equivalent Coordinate with a latitude clamped to
+[-pi/2, pi/2], correcting floating point overshoot at the poles, and
+the longitude wrapped into (-pi, pi].
+
+A *CoordinateError is returned if the Coordinate is not valid.
+*/
+func (coord Coordinate) Normalize() (Coordinate, error) {
+	if err := coord.Validate(); err != nil {
+		return Coordinate{}, err
+	}
+	latitude := math.Max(-math.Pi/2, math.Min(math.Pi/2, coord.Latitude))
+	return Coordinate{latitude, wrapLongitude(coord.Longitude)}, nil
+}
+
+// wrapLongitude returns the equivalent longitude in (-pi, pi]
+func wrapLongitude(longitude float64) float64 {
+	longitude = math.Remainder(longitude, 2*math.Pi)
+	if longitude <= -math.Pi {
+		longitude += 2 * math.Pi
+	}
+	return longitude
+}
+
+// acos is math.Acos with its argument clamped to [-1, 1] to absorb floating point error
+func acos(x float64) float64 {
+	return math.Acos(math.Max(-1, math.Min(1, x)))
+}
+
+// asin is math.Asin with its argument clamped to [-1, 1] to absorb floating point error
+func asin(x float64) float64 {
+	return math.Asin(math.Max(-1, math.Min(1, x)))
+}
@@ -0,0 +1,134 @@
+package greatcircle
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+var normalizeCoordinates = []struct {
+	coord    Coordinate
+	expected Coordinate
+	err      error
+}{
+	{coordKSFO.Coord, coordKSFO.Coord, nil},
+	{Coordinate{math.Pi/2 + 1e-12, 0}, Coordinate{math.Pi / 2, 0}, nil},
+	{Coordinate{-math.Pi/2 - 1e-12, 0}, Coordinate{-math.Pi / 2, 0}, nil},
+	{Coordinate{0, 3 * math.Pi / 2}, Coordinate{0, -math.Pi / 2}, nil},
+	{Coordinate{0, -math.Pi}, Coordinate{0, math.Pi}, nil},
+	{Coordinate{0, 4 * math.Pi}, Coordinate{0, 0}, nil},
+	{Coordinate{4, 0}, Coordinate{}, ErrInvalidLatitude},
+	{Coordinate{math.NaN(), 0}, Coordinate{}, ErrInvalidLatitude},
+	{Coordinate{0, math.NaN()}, Coordinate{}, ErrInvalidLongitude},
+	{Coordinate{0, math.Inf(-1)}, Coordinate{}, ErrInvalidLongitude},
+}
+
+func TestNormalize(t *testing.T) {
+	for _, v := range normalizeCoordinates {
+		result, err := v.coord.Normalize()
+		if !errors.Is(err, v.err) {
+			t.Fatalf("Normalizing %v expected error %v, received %v", v.coord, v.err, err)
+		}
+		if err != nil {
+			var coordinateError *CoordinateError
+			if !errors.As(err, &coordinateError) {
+				t.Fatalf("Expected a CoordinateError, received %v", err)
+			}
+			continue
+		}
+		if math.Abs(result.Latitude-v.expected.Latitude) > 1e-15 || math.Abs(result.Longitude-v.expected.Longitude) > 1e-15 {
+			t.Fatalf("Normalizing %v expected: %v, received %v", v.coord, v.expected, result)
+		}
+		if result.Validate() != nil {
+			t.Fatalf("Expected %v to be valid", result)
+		}
+	}
+}
+
+func TestNewCoordinateDegrees(t *testing.T) {
+	coord, err := NewCoordinateDegrees(latKSFO, longKSFO)
+	if err != nil || coord != coordKSFO.Coord {
+		t.Fatalf("Expected: %v, received %v %v", coordKSFO.Coord, coord, err)
+	}
+	_, err = NewCoordinateDegrees(91, 0)
+	if !errors.Is(err, ErrInvalidLatitude) {
+		t.Fatalf("Expected: %v, received %v", ErrInvalidLatitude, err)
+	}
+	_, err = NewCoordinate("95N", "122W")
+	if !errors.Is(err, ErrInvalidLatitude) {
+		t.Fatalf("Expected: %v, received %v", ErrInvalidLatitude, err)
+	}
+	_, err = NewCoordinate("0", "190W")
+	if !errors.Is(err, ErrInvalidLongitude) {
+		t.Fatalf("Expected: %v, received %v", ErrInvalidLongitude, err)
+	}
+	coord, err = NewCoordinateDegrees(0, 190)
+	if err != nil || !coord.Equal(Coordinate{0, DegreesToRadians(-170)}) {
+		t.Fatalf("Expected: %v, received %v %v", Coordinate{0, DegreesToRadians(-170)}, coord, err)
+	}
+}
+
+func TestNoNaN(t *testing.T) {
+	// coincident, antipodal and polar coordinates push math.Acos to the edge of its domain
+	var coords = []Coordinate{
+		coordKSFO.Coord,
+		{coordKSFO.Coord.Latitude + 1e-17, coordKSFO.Coord.Longitude},
+		{-coordKSFO.Coord.Latitude, coordKSFO.Coord.Longitude - math.Pi},
+		{math.Pi / 2, 0},
+		{-math.Pi / 2, 1},
+		{0, math.Pi},
+		{0, 0},
+		{0.1, 0.1},
+		{0.1 + 1e-16, 0.1},
+	}
+	for _, point1 := range coords {
+		for _, point2 := range coords {
+			if result := Distance(point1, point2); math.IsNaN(result) {
+				t.Fatalf("Distance between %v %v is NaN", point1, point2)
+			}
+			if result := InitialBearing(point1, point2); math.IsNaN(result) {
+				t.Fatalf("Initial bearing from %v to %v is NaN", point1, point2)
+			}
+			for _, bearing := range []float64{0, 1, math.Pi} {
+				result, err := IntersectionRadials(Radial{point1, bearing}, Radial{point2, 2})
+				if err == nil && (math.IsNaN(result.Latitude) || math.IsNaN(result.Longitude)) {
+					t.Fatalf("Intersection of radials from %v %v is NaN", point1, point2)
+				}
+			}
+		}
+	}
+}
+
+func TestLatLonDegrees(t *testing.T) {
+	// San Francisco is west, Sydney south and east
+	result, err := FromLatLonDegrees(latKSFO, -longKSFO)
+	if err != nil {
+		t.Fatalf("Error creating coordinate; error %v", err)
+	}
+	if !result.Equal(coordKSFO.Coord) {
+		t.Fatalf("Expected: %v, received %v", coordKSFO.Coord, result)
+	}
+	latitude, longitude := coordKSFO.Coord.LatLonDegrees()
+	if math.Abs(latitude-latKSFO) > 1e-12 || math.Abs(longitude+longKSFO) > 1e-12 {
+		t.Fatalf("Expected: %v %v, received %v %v", latKSFO, -longKSFO, latitude, longitude)
+	}
+
+	sydney, err := FromLatLonDegrees(-33.946, 151.177)
+	if err != nil {
+		t.Fatalf("Error creating coordinate; error %v", err)
+	}
+	if sydney.Latitude >= 0 || sydney.Longitude >= 0 {
+		t.Fatalf("Expected South and East to be negative, received %v", sydney)
+	}
+	if latitude, longitude := sydney.LatLonDegrees(); math.Abs(latitude+33.946) > 1e-12 || math.Abs(longitude-151.177) > 1e-12 {
+		t.Fatalf("Expected: -33.946 151.177, received %v %v", latitude, longitude)
+	}
+
+	radians, err := FromLatLonRadians(sydney.LatLonRadians())
+	if err != nil || radians != sydney {
+		t.Fatalf("Expected: %v, received %v %v", sydney, radians, err)
+	}
+	if _, err := FromLatLonDegrees(91, 0); !errors.Is(err, ErrInvalidLatitude) {
+		t.Fatalf("Expected: %v, received %v", ErrInvalidLatitude, err)
+	}
+}
@@ -0,0 +1,210 @@
+package greatcircle
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+/*
+Wind is the wind at a place and altitude: the true direction it blows from,
+in radians, and its speed in knots.
+*/
+type Wind struct {
+	Direction float64
+	Speed     float64
+}
+
+// ErrWindTooStrong is returned when the wind is too strong for a leg to be flown at the true airspeed.
+var ErrWindTooStrong = errors.New("wind too strong for the true airspeed")
+
+/*
+WindTriangle solves the wind triangle for a true course in radians flown at
+trueAirspeed knots, returning the true heading in radians, with due North 2pi
+as for InitialBearing, and the groundspeed in knots.
+
+An error wrapping ErrWindTooStrong is returned, rather than NaN, when the
+crosswind is stronger than trueAirspeed, so no heading holds the course, or
+the headwind leaves no groundspeed.
+*/
+func WindTriangle(course, trueAirspeed float64, wind Wind) (heading, groundspeed float64, err error) {
+	if !(trueAirspeed > 0) {
+		return 0, 0, fmt.Errorf("greatcircle: true airspeed %v is not positive", trueAirspeed)
+	}
+	// the sine of the wind correction angle
+	correction := wind.Speed / trueAirspeed * math.Sin(wind.Direction-course)
+	if math.Abs(correction) > 1 {
+		return 0, 0, fmt.Errorf("greatcircle: crosswind of %v knots: %w", wind.Speed*math.Abs(math.Sin(wind.Direction-course)), ErrWindTooStrong)
+	}
+	heading = normalizeBearing(course + math.Asin(correction))
+	groundspeed = trueAirspeed*math.Sqrt(1-correction*correction) - wind.Speed*math.Cos(wind.Direction-course)
+	if groundspeed <= 0 {
+		return 0, 0, fmt.Errorf("greatcircle: groundspeed of %v knots: %w", groundspeed, ErrWindTooStrong)
+	}
+	return heading, groundspeed, nil
+}
+
+/*
+WindModel gives the wind on each leg of a route: leg is the index of the leg,
+as for LegTypedRoute.LegType, and altitude the altitude flown in feet.
+
+A Wind is itself a WindModel, the same wind everywhere.
+*/
+type WindModel interface {
+	LegWind(leg int, altitude float64) Wind
+}
+
+// LegWind returns the Wind, whatever the leg and altitude
+func (wind Wind) LegWind(leg int, altitude float64) Wind {
+	return wind
+}
+
+// WindFunc adapts a function to a WindModel.
+type WindFunc func(leg int, altitude float64) Wind
+
+// LegWind calls the function
+func (f WindFunc) LegWind(leg int, altitude float64) Wind {
+	return f(leg, altitude)
+}
+
+/*
+LegWinds is a WindModel with a wind for each leg of a route. Legs beyond the
+last are given the last wind, and an empty LegWinds is calm.
+*/
+type LegWinds []Wind
+
+// LegWind returns the wind for the leg, whatever the altitude
+func (winds LegWinds) LegWind(leg int, altitude float64) Wind {
+	if len(winds) == 0 {
+		return Wind{}
+	}
+	return winds[clampIndex(leg, len(winds))]
+}
+
+/*
+WindAloft is the wind forecast at an altitude in feet.
+*/
+type WindAloft struct {
+	Altitude float64
+	Wind     Wind
+}
+
+/*
+WindsAloft is a WindModel from winds forecast at several altitudes, the same on
+every leg. Between the forecast altitudes the wind is interpolated; above and
+below them the nearest is used. An empty WindsAloft is calm.
+*/
+type WindsAloft []WindAloft
+
+// LegWind returns the wind at the altitude, whatever the leg
+func (winds WindsAloft) LegWind(leg int, altitude float64) Wind {
+	if len(winds) == 0 {
+		return Wind{}
+	}
+	sorted := append(WindsAloft(nil), winds...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Altitude < sorted[j].Altitude
+	})
+	above := sort.Search(len(sorted), func(i int) bool {
+		return sorted[i].Altitude >= altitude
+	})
+	if above == 0 {
+		return sorted[0].Wind
+	}
+	if above == len(sorted) {
+		return sorted[len(sorted)-1].Wind
+	}
+	lower, upper := sorted[above-1], sorted[above]
+	fraction := (altitude - lower.Altitude) / (upper.Altitude - lower.Altitude)
+	// interpolate the components of the wind, not its direction and speed
+	north := (1-fraction)*lower.Wind.Speed*math.Cos(lower.Wind.Direction) + fraction*upper.Wind.Speed*math.Cos(upper.Wind.Direction)
+	east := (1-fraction)*lower.Wind.Speed*math.Sin(lower.Wind.Direction) + fraction*upper.Wind.Speed*math.Sin(upper.Wind.Direction)
+	return Wind{normalizeBearing(math.Atan2(east, north)), math.Hypot(north, east)}
+}
+
+/*
+FlightPlan describes how a route is flown: at TrueAirspeed knots, at Altitudes
+in feet, one for each leg with the last used for any further legs, in the wind
+of Winds, leaving at Departure.
+*/
+type FlightPlan struct {
+	TrueAirspeed float64
+	Altitudes    []float64
+	Winds        WindModel
+	Departure    time.Time
+}
+
+/*
+WindLeg is a RouteLeg flown according to a FlightPlan. The wind is solved for
+the InitialCourse of the leg.
+*/
+type WindLeg struct {
+	RouteLeg
+	Altitude float64
+	Wind     Wind
+	// TrueHeading is in radians
+	TrueHeading float64
+	// GroundSpeed is in knots
+	GroundSpeed float64
+	// TimeEnRoute is the time to fly the leg
+	TimeEnRoute time.Duration
+	// ETA is the time of arrival at To, or zero if the FlightPlan has no Departure
+	ETA time.Time
+}
+
+/*
+WindLog is a NavLog of the legs of a route flown according to a FlightPlan,
+with the totals for the route.
+*/
+type WindLog struct {
+	Legs []WindLeg
+	// Distance is the total length of the route in nautical miles
+	Distance    float64
+	TimeEnRoute time.Duration
+	// ETA is the time of arrival at the end of the route, or zero if the FlightPlan has no Departure
+	ETA time.Time
+}
+
+/*
+WindLog breaks the route down into its legs, each a great circle, and solves
+the wind triangle for each according to plan, as LegTypedRoute's WindLog does.
+*/
+func (route MultiPointRoute) WindLog(plan FlightPlan) (WindLog, error) {
+	return route.WithLegTypes().WindLog(plan)
+}
+
+/*
+WindLog breaks the route down into its legs, as NavLog does, and solves the
+wind triangle for each according to plan.
+
+An error wrapping ErrWindTooStrong, naming the leg, is returned if a leg
+cannot be flown.
+*/
+func (route LegTypedRoute) WindLog(plan FlightPlan) (WindLog, error) {
+	navLog := route.NavLog()
+	windLog := WindLog{Distance: navLog.Distance, ETA: plan.Departure}
+	for leg, routeLeg := range navLog.Legs {
+		windLeg := WindLeg{RouteLeg: routeLeg}
+		if len(plan.Altitudes) > 0 {
+			windLeg.Altitude = plan.Altitudes[clampIndex(leg, len(plan.Altitudes))]
+		}
+		if plan.Winds != nil {
+			windLeg.Wind = plan.Winds.LegWind(leg, windLeg.Altitude)
+		}
+		heading, groundspeed, err := WindTriangle(routeLeg.InitialCourse, plan.TrueAirspeed, windLeg.Wind)
+		if err != nil {
+			return WindLog{}, fmt.Errorf("leg %d: %w", leg, err)
+		}
+		windLeg.TrueHeading, windLeg.GroundSpeed = heading, groundspeed
+		windLeg.TimeEnRoute = time.Duration(routeLeg.Distance / groundspeed * float64(time.Hour))
+		windLog.TimeEnRoute = windLog.TimeEnRoute + windLeg.TimeEnRoute
+		if !plan.Departure.IsZero() {
+			windLeg.ETA = plan.Departure.Add(windLog.TimeEnRoute)
+			windLog.ETA = windLeg.ETA
+		}
+		windLog.Legs = append(windLog.Legs, windLeg)
+	}
+	return windLog, nil
+}
@@ -0,0 +1,157 @@
+package greatcircle
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWindTriangle(t *testing.T) {
+	cases := []struct {
+		course, trueAirspeed float64
+		wind                 Wind
+		heading, groundspeed float64
+	}{
+		{90, 100, Wind{}, 90, 100},
+		{360, 100, Wind{DegreesToRadians(360), 30}, 360, 70},
+		{360, 100, Wind{DegreesToRadians(180), 30}, 360, 130},
+		{90, 100, Wind{DegreesToRadians(360), 20}, 90 - RadiansToDegrees(math.Asin(0.2)), 100 * math.Sqrt(0.96)},
+		{270, 100, Wind{DegreesToRadians(360), 20}, 270 + RadiansToDegrees(math.Asin(0.2)), 100 * math.Sqrt(0.96)},
+		{10, 100, Wind{DegreesToRadians(350), 20}, 10 - RadiansToDegrees(math.Asin(0.2*math.Sin(DegreesToRadians(20)))), 0},
+	}
+	for _, c := range cases {
+		heading, groundspeed, err := WindTriangle(DegreesToRadians(c.course), c.trueAirspeed, c.wind)
+		if err != nil {
+			t.Fatalf("Error solving for %v; error %v", c.course, err)
+		}
+		if math.Abs(RadiansToDegrees(heading)-c.heading) > 1e-9 {
+			t.Fatalf("Expected: %v, received %v", c.heading, RadiansToDegrees(heading))
+		}
+		if c.groundspeed != 0 && math.Abs(groundspeed-c.groundspeed) > 1e-9 {
+			t.Fatalf("Expected: %v, received %v", c.groundspeed, groundspeed)
+		}
+	}
+
+	errorCases := []struct {
+		course, trueAirspeed float64
+		wind                 Wind
+	}{
+		{90, 100, Wind{DegreesToRadians(360), 120}},
+		{360, 100, Wind{DegreesToRadians(360), 100}},
+		{360, 100, Wind{DegreesToRadians(360), 150}},
+	}
+	for _, c := range errorCases {
+		heading, groundspeed, err := WindTriangle(DegreesToRadians(c.course), c.trueAirspeed, c.wind)
+		if !errors.Is(err, ErrWindTooStrong) || heading != 0 || groundspeed != 0 {
+			t.Fatalf("Expected: %v, received %v, %v, %v", ErrWindTooStrong, heading, groundspeed, err)
+		}
+	}
+	if _, _, err := WindTriangle(0, 0, Wind{}); err == nil || errors.Is(err, ErrWindTooStrong) {
+		t.Fatalf("Expected an error for no airspeed, received %v", err)
+	}
+}
+
+func TestWindModels(t *testing.T) {
+	westerly := Wind{DegreesToRadians(270), 10}
+	if result := westerly.LegWind(3, 5000); result != westerly {
+		t.Fatalf("Expected: %v, received %v", westerly, result)
+	}
+
+	legWinds := LegWinds{westerly, {DegreesToRadians(90), 20}}
+	for _, c := range []struct {
+		leg      int
+		expected Wind
+	}{{0, westerly}, {1, legWinds[1]}, {5, legWinds[1]}} {
+		if result := legWinds.LegWind(c.leg, 0); result != c.expected {
+			t.Fatalf("Expected: %v, received %v", c.expected, result)
+		}
+	}
+	if result := (LegWinds{}).LegWind(0, 0); result != (Wind{}) {
+		t.Fatalf("Expected calm, received %v", result)
+	}
+
+	windsAloft := WindsAloft{
+		{10000, Wind{DegreesToRadians(270), 30}},
+		{0, westerly},
+		{20000, Wind{DegreesToRadians(10), 30}},
+	}
+	cases := []struct {
+		altitude  float64
+		direction float64
+		speed     float64
+	}{
+		{-500, 270, 10},
+		{0, 270, 10},
+		{5000, 270, 20},
+		{10000, 270, 30},
+		{40000, 10, 30},
+	}
+	for _, c := range cases {
+		result := windsAloft.LegWind(0, c.altitude)
+		if math.Abs(RadiansToDegrees(result.Direction)-c.direction) > 1e-9 || math.Abs(result.Speed-c.speed) > 1e-9 {
+			t.Fatalf("Expected: %v at %v, received %v at %v", c.direction, c.speed, RadiansToDegrees(result.Direction), result.Speed)
+		}
+	}
+	// between 270 and 010 the wind backs through north west, not south
+	if result := windsAloft.LegWind(0, 15000); RadiansToDegrees(result.Direction) < 270 || RadiansToDegrees(result.Direction) > 360 {
+		t.Fatalf("Expected: a north westerly, received %v", RadiansToDegrees(result.Direction))
+	}
+
+	byLeg := WindFunc(func(leg int, altitude float64) Wind {
+		return Wind{DegreesToRadians(float64(leg+1) * 90), altitude / 1000}
+	})
+	if result := byLeg.LegWind(1, 8000); result != (Wind{DegreesToRadians(180), 8}) {
+		t.Fatalf("Expected: 180 at 8, received %v", result)
+	}
+}
+
+func TestWindLog(t *testing.T) {
+	route := NewMultiPointRoute([]NamedCoordinate{coordKSFO, coordKSJC, coordKLAX})
+	departure := time.Date(2020, 6, 1, 16, 0, 0, 0, time.UTC)
+
+	calm, err := route.WindLog(FlightPlan{TrueAirspeed: 120, Departure: departure})
+	if err != nil {
+		t.Fatalf("Error solving; error %v", err)
+	}
+	if len(calm.Legs) != 2 || calm.Distance != route.NavLog().Distance {
+		t.Fatalf("Expected: 2 legs of %v, received %v", route.NavLog().Distance, calm)
+	}
+	for _, leg := range calm.Legs {
+		if leg.GroundSpeed != 120 || leg.TrueHeading != leg.InitialCourse {
+			t.Fatalf("Expected: %v at 120, received %v at %v", leg.InitialCourse, leg.TrueHeading, leg.GroundSpeed)
+		}
+		if expected := time.Duration(leg.Distance / 120 * float64(time.Hour)); leg.TimeEnRoute != expected {
+			t.Fatalf("Expected: %v, received %v", expected, leg.TimeEnRoute)
+		}
+	}
+	if calm.TimeEnRoute != calm.Legs[0].TimeEnRoute+calm.Legs[1].TimeEnRoute {
+		t.Fatalf("Expected: %v, received %v", calm.Legs[0].TimeEnRoute+calm.Legs[1].TimeEnRoute, calm.TimeEnRoute)
+	}
+	if !calm.Legs[0].ETA.Equal(departure.Add(calm.Legs[0].TimeEnRoute)) || !calm.ETA.Equal(departure.Add(calm.TimeEnRoute)) {
+		t.Fatalf("Expected: %v, received %v and %v", departure.Add(calm.TimeEnRoute), calm.Legs[0].ETA, calm.ETA)
+	}
+
+	// a north westerly speeds up the flight south east, more so at altitude
+	plan := FlightPlan{
+		TrueAirspeed: 120,
+		Altitudes:    []float64{3000, 9000},
+		Winds:        WindsAloft{{0, Wind{DegreesToRadians(315), 10}}, {12000, Wind{DegreesToRadians(315), 40}}},
+	}
+	windy, err := route.WindLog(plan)
+	if err != nil {
+		t.Fatalf("Error solving; error %v", err)
+	}
+	if windy.Legs[0].Altitude != 3000 || math.Abs(windy.Legs[1].Wind.Speed-32.5) > 1e-9 {
+		t.Fatalf("Expected: 3000 and 32.5, received %v and %v", windy.Legs[0].Altitude, windy.Legs[1].Wind.Speed)
+	}
+	if windy.TimeEnRoute >= calm.TimeEnRoute || !windy.ETA.IsZero() {
+		t.Fatalf("Expected: less than %v with no ETA, received %v and %v", calm.TimeEnRoute, windy.TimeEnRoute, windy.ETA)
+	}
+
+	plan.Winds = LegWinds{{}, {DegreesToRadians(135), 150}}
+	if _, err := route.WindLog(plan); !errors.Is(err, ErrWindTooStrong) || !strings.HasPrefix(err.Error(), "leg 1: greatcircle: ") {
+		t.Fatalf("Expected: %v on leg 1, received %v", ErrWindTooStrong, err)
+	}
+}
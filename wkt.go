@@ -0,0 +1,161 @@
+package greatcircle
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+/*
+Well-known text (WKT) and binary (WKB) are how PostGIS and other spatial
+databases exchange geometries. Positions are (x y), that is longitude then
+latitude in degrees, East positive.
+*/
+
+// ErrGeometry is returned when WKT or WKB does not contain the expected geometry.
+var ErrGeometry = errors.New("unexpected geometry")
+
+const (
+	wkbPoint      = 1
+	wkbLineString = 2
+)
+
+// ewkt writes coords as an EWKT POINT or LINESTRING with the WGS 84 spatial reference
+func ewkt(geometry string, coords []Coordinate) string {
+	positions := make([]string, len(coords))
+	for i, coord := range coords {
+		latitude, longitude := coord.LatLonDegrees()
+		positions[i] = strconv.FormatFloat(longitude, 'f', -1, 64) + " " + strconv.FormatFloat(latitude, 'f', -1, 64)
+	}
+	return "SRID=4326;" + geometry + "(" + strings.Join(positions, ",") + ")"
+}
+
+/*
+parseGeometry parses WKT, EWKT, WKB, EWKB, or either binary form hex encoded as
+PostGIS returns them, into the positions of a POINT or LINESTRING.
+*/
+func parseGeometry(data []byte) (int, []Coordinate, error) {
+	text := strings.TrimSpace(string(data))
+	if len(data) > 0 && data[0] <= 1 {
+		return parseWKB(data)
+	}
+	if decoded, err := hex.DecodeString(text); err == nil && len(decoded) > 0 {
+		return parseWKB(decoded)
+	}
+	return parseWKT(text)
+}
+
+func parseWKT(text string) (int, []Coordinate, error) {
+	if strings.HasPrefix(strings.ToUpper(text), "SRID=") {
+		semicolon := strings.IndexByte(text, ';')
+		if semicolon < 0 {
+			return 0, nil, fmt.Errorf("greatcircle: WKT %q: %w", text, ErrGeometry)
+		}
+		text = text[semicolon+1:]
+	}
+	open, end := strings.IndexByte(text, '('), strings.LastIndexByte(text, ')')
+	if open < 0 || end < open {
+		return 0, nil, fmt.Errorf("greatcircle: WKT %q: %w", text, ErrGeometry)
+	}
+	var geometry int
+	switch strings.Fields(strings.ToUpper(text[:open]) + " ")[0] {
+	case "POINT":
+		geometry = wkbPoint
+	case "LINESTRING":
+		geometry = wkbLineString
+	default:
+		return 0, nil, fmt.Errorf("greatcircle: WKT %q is not a POINT or LINESTRING: %w", text, ErrGeometry)
+	}
+	var coords []Coordinate
+	for _, position := range strings.Split(text[open+1:end], ",") {
+		values := strings.Fields(position)
+		if len(values) < 2 {
+			return 0, nil, fmt.Errorf("greatcircle: WKT position %q: %w", position, ErrGeometry)
+		}
+		longitude, err := strconv.ParseFloat(values[0], 64)
+		if err != nil {
+			return 0, nil, err
+		}
+		latitude, err := strconv.ParseFloat(values[1], 64)
+		if err != nil {
+			return 0, nil, err
+		}
+		coord, err := FromLatLonDegrees(latitude, longitude)
+		if err != nil {
+			return 0, nil, err
+		}
+		coords = append(coords, coord)
+	}
+	if geometry == wkbPoint && len(coords) != 1 {
+		return 0, nil, fmt.Errorf("greatcircle: WKT %q: %w", text, ErrGeometry)
+	}
+	return geometry, coords, nil
+}
+
+func parseWKB(data []byte) (int, []Coordinate, error) {
+	short := fmt.Errorf("greatcircle: WKB too short: %w", ErrGeometry)
+	if len(data) < 5 {
+		return 0, nil, short
+	}
+	var order binary.ByteOrder = binary.BigEndian
+	if data[0] == 1 {
+		order = binary.LittleEndian
+	}
+	wkbType := order.Uint32(data[1:])
+	data = data[5:]
+
+	// the EWKB flags used by PostGIS, or the ISO WKB thousands
+	dimensions := 2
+	if wkbType&0x80000000 != 0 {
+		dimensions++
+	}
+	if wkbType&0x40000000 != 0 {
+		dimensions++
+	}
+	if wkbType&0x20000000 != 0 {
+		if len(data) < 4 {
+			return 0, nil, short
+		}
+		data = data[4:]
+	}
+	wkbType = wkbType & 0x0fffffff
+	switch wkbType / 1000 {
+	case 1, 2:
+		dimensions++
+	case 3:
+		dimensions += 2
+	}
+	geometry := int(wkbType % 1000)
+
+	count := 1
+	switch geometry {
+	case wkbPoint:
+	case wkbLineString:
+		if len(data) < 4 {
+			return 0, nil, short
+		}
+		count = int(order.Uint32(data))
+		data = data[4:]
+	default:
+		return 0, nil, fmt.Errorf("greatcircle: WKB type %d is not a POINT or LINESTRING: %w", geometry, ErrGeometry)
+	}
+	if len(data) < count*dimensions*8 {
+		return 0, nil, short
+	}
+	coords := make([]Coordinate, count)
+	for i := range coords {
+		longitude := math.Float64frombits(order.Uint64(data))
+		latitude := math.Float64frombits(order.Uint64(data[8:]))
+		data = data[dimensions*8:]
+		coord, err := FromLatLonDegrees(latitude, longitude)
+		if err != nil {
+			return 0, nil, err
+		}
+		coords[i] = coord
+	}
+	return geometry, coords, nil
+}
@@ -0,0 +1,144 @@
+package greatcircle
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"math"
+	"testing"
+)
+
+// wkb builds WKB in the given byte order, with the SRID when it is not zero
+func wkb(order binary.AppendByteOrder, wkbType uint32, srid uint32, positions ...[]float64) []byte {
+	data := []byte{0}
+	if order == binary.LittleEndian {
+		data[0] = 1
+	}
+	if srid != 0 {
+		wkbType |= 0x20000000
+	}
+	data = order.AppendUint32(data, wkbType)
+	if srid != 0 {
+		data = order.AppendUint32(data, srid)
+	}
+	if wkbType&0x0fffffff%1000 == wkbLineString {
+		data = order.AppendUint32(data, uint32(len(positions)))
+	}
+	for _, position := range positions {
+		for _, value := range position {
+			data = order.AppendUint64(data, math.Float64bits(value))
+		}
+	}
+	return data
+}
+
+func TestParseGeometry(t *testing.T) {
+	ksfo, _ := FromLatLonDegrees(37.5, -122.25)
+	klax, _ := FromLatLonDegrees(33.875, -118.5)
+	point := []Coordinate{ksfo}
+	line := []Coordinate{ksfo, klax}
+
+	tests := []struct {
+		data     []byte
+		geometry int
+		coords   []Coordinate
+	}{
+		{[]byte("POINT(-122.25 37.5)"), wkbPoint, point},
+		{[]byte("  point (-122.25 37.5)  "), wkbPoint, point},
+		{[]byte("SRID=4326;POINT(-122.25 37.5)"), wkbPoint, point},
+		{[]byte("POINT Z (-122.25 37.5 10)"), wkbPoint, point},
+		{[]byte("LINESTRING(-122.25 37.5, -118.5 33.875)"), wkbLineString, line},
+		{[]byte("SRID=4326;LINESTRING(-122.25 37.5,-118.5 33.875)"), wkbLineString, line},
+		{wkb(binary.BigEndian, wkbPoint, 0, []float64{-122.25, 37.5}), wkbPoint, point},
+		{wkb(binary.LittleEndian, wkbPoint, 0, []float64{-122.25, 37.5}), wkbPoint, point},
+		{wkb(binary.BigEndian, wkbPoint, 4326, []float64{-122.25, 37.5}), wkbPoint, point},
+		{wkb(binary.LittleEndian, wkbPoint, 4326, []float64{-122.25, 37.5}), wkbPoint, point},
+		{wkb(binary.LittleEndian, wkbPoint|0x80000000, 4326, []float64{-122.25, 37.5, 10}), wkbPoint, point},
+		{wkb(binary.LittleEndian, 1000+wkbPoint, 0, []float64{-122.25, 37.5, 10}), wkbPoint, point},
+		{wkb(binary.LittleEndian, 3000+wkbPoint, 0, []float64{-122.25, 37.5, 10, 5}), wkbPoint, point},
+		{wkb(binary.BigEndian, wkbLineString, 0, []float64{-122.25, 37.5}, []float64{-118.5, 33.875}), wkbLineString, line},
+		{wkb(binary.LittleEndian, wkbLineString, 4326, []float64{-122.25, 37.5}, []float64{-118.5, 33.875}), wkbLineString, line},
+		{wkb(binary.LittleEndian, 2000+wkbLineString, 0, []float64{-122.25, 37.5, 1}, []float64{-118.5, 33.875, 2}), wkbLineString, line},
+		{[]byte(hex.EncodeToString(wkb(binary.LittleEndian, wkbPoint, 4326, []float64{-122.25, 37.5}))), wkbPoint, point},
+		{[]byte(hex.EncodeToString(wkb(binary.BigEndian, wkbLineString, 4326, []float64{-122.25, 37.5}, []float64{-118.5, 33.875}))), wkbLineString, line},
+	}
+
+	for _, test := range tests {
+		geometry, coords, err := parseGeometry(test.data)
+		if err != nil {
+			t.Fatalf("Error parsing %q; error %v", test.data, err)
+		}
+		if geometry != test.geometry || len(coords) != len(test.coords) {
+			t.Fatalf("Expected: %d %v, received %d %v", test.geometry, test.coords, geometry, coords)
+		}
+		for i := range coords {
+			if !coords[i].Equal(test.coords[i]) {
+				t.Fatalf("Expected: %v, received %v", test.coords[i], coords[i])
+			}
+		}
+	}
+}
+
+func TestEWKTRoundTrip(t *testing.T) {
+	ksfo, _ := FromLatLonDegrees(37.618889, -122.375)
+	klax, _ := FromLatLonDegrees(33.9425, -118.25)
+	tests := []struct {
+		geometry string
+		wkbType  int
+		coords   []Coordinate
+		expected string
+	}{
+		{"POINT", wkbPoint, []Coordinate{ksfo}, "SRID=4326;POINT(-122.375 37.618889)"},
+		{"LINESTRING", wkbLineString, []Coordinate{ksfo, klax}, "SRID=4326;LINESTRING(-122.375 37.618889,-118.25 33.9425)"},
+	}
+
+	for _, test := range tests {
+		text := ewkt(test.geometry, test.coords)
+		if text != test.expected {
+			t.Fatalf("Expected: %s, received %s", test.expected, text)
+		}
+		geometry, coords, err := parseGeometry([]byte(text))
+		if err != nil || geometry != test.wkbType || len(coords) != len(test.coords) {
+			t.Fatalf("Expected: %d %v, received %d %v, %v", test.wkbType, test.coords, geometry, coords, err)
+		}
+		for i := range coords {
+			if !coords[i].Equal(test.coords[i]) {
+				t.Fatalf("Expected: %v, received %v", test.coords[i], coords[i])
+			}
+		}
+	}
+}
+
+func TestParseGeometryErrors(t *testing.T) {
+	tests := []struct {
+		data     []byte
+		geometry bool
+	}{
+		{[]byte("POLYGON((0 0,1 0,1 1,0 0))"), true},
+		{[]byte("POINT"), true},
+		{[]byte("POINT)-122.25 37.5("), true},
+		{[]byte("POINT(-122.25)"), true},
+		{[]byte("POINT(-122.25 37.5,-118.5 33.875)"), true},
+		{[]byte("SRID=4326POINT(-122.25 37.5)"), true},
+		{[]byte("POINT(west 37.5)"), false},
+		{[]byte("POINT(-122.25 north)"), false},
+		{[]byte("POINT(-122.25 91)"), false},
+		{[]byte{1, 1, 0}, true},
+		{wkb(binary.LittleEndian, wkbPoint, 0, []float64{-122.25}), true},
+		{wkb(binary.BigEndian, wkbLineString, 0, []float64{-122.25, 37.5}, []float64{-118.5})[:25], true},
+		{wkb(binary.LittleEndian, wkbPoint, 0)[:5], true},
+		{wkb(binary.LittleEndian, 3, 0, []float64{0, 0}), true},
+		{append(wkb(binary.LittleEndian, wkbPoint|0x20000000, 0), 0xe6, 0x10), true},
+		{wkb(binary.LittleEndian, wkbPoint, 0, []float64{-122.25, 91}), false},
+	}
+
+	for _, test := range tests {
+		_, coords, err := parseGeometry(test.data)
+		if err == nil {
+			t.Fatalf("Expected: error parsing %q, received %v", test.data, coords)
+		}
+		if errors.Is(err, ErrGeometry) != test.geometry {
+			t.Fatalf("Expected: ErrGeometry %t for %q, received %v", test.geometry, test.data, err)
+		}
+	}
+}